@@ -0,0 +1,196 @@
+package slogleveloverride
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+)
+
+// ActuatorRoot is the logger name addressing [ActuatorHandler.Handler].
+const ActuatorRoot = "ROOT"
+
+// levelOff is the level set for the OFF level of the Actuator API, above any
+// level in use.
+const levelOff = slog.Level(math.MaxInt32)
+
+// actuatorLevels are the levels of the Actuator API, from least to most
+// verbose.
+var actuatorLevels = []struct {
+	name  string
+	level slog.Level
+}{
+	{"OFF", levelOff},
+	{"FATAL", LevelFatal},
+	{"ERROR", slog.LevelError},
+	{"WARN", slog.LevelWarn},
+	{"INFO", slog.LevelInfo},
+	{"DEBUG", slog.LevelDebug},
+	{"TRACE", LevelTrace},
+}
+
+// ActuatorHandler is an [http.Handler] serving the loggers endpoint of the
+// Spring Boot Actuator API, so that tooling built for Spring services can
+// manage levels the same way:
+//
+//	GET  /loggers         levels of ROOT and all registered handlers
+//	GET  /loggers/{name}  level of one logger
+//	POST /loggers/{name}  set the level with {"configuredLevel": "DEBUG"},
+//	                      or remove the override with null
+//
+// The logger named [ActuatorRoot] is Handler; other names address Registry.
+// Mount it under the Actuator base path with [http.StripPrefix], e.g.
+// mux.Handle("/actuator/loggers/", http.StripPrefix("/actuator", h)).
+//
+// Levels are reported as the nearest Actuator level at or below them, and
+// changes are subject to the policy set with [SetPolicy].
+type ActuatorHandler struct {
+	// Handler is the ROOT logger. It may be nil.
+	Handler *OverrideHandler
+	// Registry holds the named loggers. It may be nil.
+	Registry *Registry
+}
+
+// actuatorLogger is the representation of a logger in the Actuator API.
+type actuatorLogger struct {
+	ConfiguredLevel *string `json:"configuredLevel"`
+	EffectiveLevel  string  `json:"effectiveLevel"`
+}
+
+// ServeHTTP serves the Actuator loggers API.
+func (a *ActuatorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, found := strings.CutPrefix(r.URL.Path, "/loggers")
+	name = strings.TrimPrefix(name, "/")
+	switch {
+	case !found || strings.Contains(name, "/"):
+		http.NotFound(w, r)
+	case name == "" && r.Method == http.MethodGet:
+		a.list(w, r.Context())
+	case name == "":
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	case r.Method == http.MethodGet:
+		h, ok := a.logger(name)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, describeLogger(r.Context(), h))
+	case r.Method == http.MethodPost:
+		a.set(w, r, name)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// list writes the levels and all loggers.
+func (a *ActuatorHandler) list(w http.ResponseWriter, ctx context.Context) {
+	levels := make([]string, len(actuatorLevels))
+	for i, l := range actuatorLevels {
+		levels[i] = l.name
+	}
+	loggers := make(map[string]actuatorLogger)
+	if a.Handler != nil {
+		loggers[ActuatorRoot] = describeLogger(ctx, a.Handler)
+	}
+	if a.Registry != nil {
+		for _, name := range a.Registry.Names() {
+			if h, ok := a.Registry.Handler(name); ok {
+				loggers[name] = describeLogger(ctx, h)
+			}
+		}
+	}
+	writeJSON(w, map[string]any{
+		"levels":  levels,
+		"loggers": loggers,
+		"groups":  map[string]any{},
+	})
+}
+
+// set handles a POST request changing the level of the logger name.
+func (a *ActuatorHandler) set(w http.ResponseWriter, r *http.Request, name string) {
+	h, ok := a.logger(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	var body struct {
+		ConfiguredLevel *string `json:"configuredLevel"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if body.ConfiguredLevel == nil {
+		h.ClearLevel()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	level, err := parseActuatorLevel(*body.ConfiguredLevel)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	target := name
+	if name == ActuatorRoot {
+		target = ""
+	}
+	if err := h.setLevel(target, SourceHTTP, level); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// logger returns the handler addressed by name.
+func (a *ActuatorHandler) logger(name string) (*OverrideHandler, bool) {
+	if name == ActuatorRoot {
+		return a.Handler, a.Handler != nil
+	}
+	if a.Registry == nil {
+		return nil, false
+	}
+	return a.Registry.Handler(name)
+}
+
+// describeLogger returns the Actuator representation of h.
+func describeLogger(ctx context.Context, h *OverrideHandler) actuatorLogger {
+	var l actuatorLogger
+	if level, ok := h.Level(); ok {
+		name := actuatorLevelName(level)
+		l.ConfiguredLevel = &name
+	}
+	l.EffectiveLevel = actuatorLevelName(h.effectiveLevel(ctx))
+	return l
+}
+
+// actuatorLevelName returns the name of the nearest Actuator level at or
+// below level.
+func actuatorLevelName(level slog.Level) string {
+	for _, l := range actuatorLevels {
+		if level >= l.level {
+			return l.name
+		}
+	}
+	return actuatorLevels[len(actuatorLevels)-1].name
+}
+
+// parseActuatorLevel parses an Actuator level name.
+func parseActuatorLevel(s string) (slog.Level, error) {
+	for _, l := range actuatorLevels {
+		if strings.EqualFold(s, l.name) {
+			return l.level, nil
+		}
+	}
+	return 0, errors.New("unknown level " + s)
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/vnd.spring-boot.actuator.v3+json")
+	_ = json.NewEncoder(w).Encode(v)
+}
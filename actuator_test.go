@@ -0,0 +1,96 @@
+package slogleveloverride
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestActuatorHandler verifies that loggers are listed and changed through the Actuator API
+func TestActuatorHandler(t *testing.T) {
+	root := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	db := New(slog.NewTextHandler(nil, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	registry := NewRegistry()
+	registry.Register("db", db)
+	server := httptest.NewServer(http.StripPrefix("/actuator", &ActuatorHandler{Handler: root, Registry: registry}))
+	defer server.Close()
+
+	var list struct {
+		Levels  []string
+		Loggers map[string]actuatorLogger
+	}
+	getJSON(t, server.URL+"/actuator/loggers", &list)
+	if got := strings.Join(list.Levels, ","); got != "OFF,FATAL,ERROR,WARN,INFO,DEBUG,TRACE" {
+		t.Errorf("levels = %s", got)
+	}
+	if l := list.Loggers[ActuatorRoot]; l.ConfiguredLevel == nil || *l.ConfiguredLevel != "INFO" || l.EffectiveLevel != "INFO" {
+		t.Errorf("ROOT = %+v", l)
+	}
+	if l := list.Loggers["db"]; l.ConfiguredLevel != nil || l.EffectiveLevel != "WARN" {
+		t.Errorf("db = %+v", l)
+	}
+
+	if code := postJSON(t, server.URL+"/actuator/loggers/db", `{"configuredLevel":"trace"}`); code != http.StatusNoContent {
+		t.Errorf("POST returned %d", code)
+	}
+	if level, ok := db.Level(); !ok || level != LevelTrace {
+		t.Errorf("db level = %v, %v, want %v", level, ok, LevelTrace)
+	}
+	var l actuatorLogger
+	getJSON(t, server.URL+"/actuator/loggers/db", &l)
+	if l.ConfiguredLevel == nil || *l.ConfiguredLevel != "TRACE" || l.EffectiveLevel != "TRACE" {
+		t.Errorf("db = %+v", l)
+	}
+
+	if code := postJSON(t, server.URL+"/actuator/loggers/db", `{"configuredLevel":null}`); code != http.StatusNoContent {
+		t.Errorf("POST null returned %d", code)
+	}
+	if _, ok := db.Level(); ok {
+		t.Error("db override should be cleared")
+	}
+	if code := postJSON(t, server.URL+"/actuator/loggers/ROOT", `{"configuredLevel":"OFF"}`); code != http.StatusNoContent {
+		t.Errorf("POST OFF returned %d", code)
+	}
+	if root.Enabled(t.Context(), LevelFatal) {
+		t.Error("ROOT should be off")
+	}
+
+	for path, want := range map[string]int{
+		"/actuator/loggers/db":      http.StatusBadRequest,
+		"/actuator/loggers/missing": http.StatusNotFound,
+	} {
+		if code := postJSON(t, server.URL+path, `{"configuredLevel":"VERBOSE"}`); code != want {
+			t.Errorf("POST %s returned %d, want %d", path, code, want)
+		}
+	}
+}
+
+// getJSON decodes the JSON response to a GET request of url into v
+func getJSON(t *testing.T, url string, v any) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s returned %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// postJSON posts body to url and returns the status code
+func postJSON(t *testing.T, url, body string) int {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
@@ -0,0 +1,70 @@
+package slogleveloverride
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// AgentDirEnvVar is the environment variable overriding the directory of
+// the sockets of [StartAgent].
+const AgentDirEnvVar = "LOGLEVEL_AGENT_DIR"
+
+// AgentSocketPath returns the path of the socket [StartAgent] listens on in
+// the process pid. The socket lives in the directory named by
+// [AgentDirEnvVar] or, by default, in a directory of the user's
+// configuration directory, like the gops agent.
+func AgentSocketPath(pid int) (string, error) {
+	dir := os.Getenv(AgentDirEnvVar)
+	if dir == "" {
+		config, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(config, "slogleveloverride")
+	}
+	return filepath.Join(dir, strconv.Itoa(pid)+".sock"), nil
+}
+
+// StartAgent serves s on a unix socket named after the process ID, at
+// [AgentSocketPath], so that the levels of any running process can be
+// changed with "loglevelctl -pid <pid>" without configuring an endpoint
+// beforehand. The directory is made accessible by the user only, including
+// when it already exists. A socket
+// left at the path by a previous process with the same ID is replaced, but
+// not one a server still accepts connections on.
+//
+// Calling stop closes the socket and removes it, and returns the error that
+// ended serving the socket, if any, besides the socket being closed.
+func StartAgent(s *ControlServer) (stop func() error, err error) {
+	path, err := AgentSocketPath(os.Getpid())
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return nil, err
+	}
+	l, err := listenUnix(path)
+	if err != nil {
+		return nil, err
+	}
+	served := make(chan error, 1)
+	go func() { served <- s.Serve(l) }()
+	return func() error {
+		err := l.Close()
+		if errors.Is(err, net.ErrClosed) {
+			err = nil
+		}
+		os.Remove(path)
+		if serveErr := <-served; !errors.Is(serveErr, ErrServerClosed) {
+			err = errors.Join(err, serveErr)
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,85 @@
+package slogleveloverride
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+// TestStartAgent verifies that the agent serves the control protocol on the socket of the process
+func TestStartAgent(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(AgentDirEnvVar, dir)
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+
+	stop, err := StartAgent(&ControlServer{Handler: handler})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, strconv.Itoa(os.Getpid())+".sock")
+	if got, _ := AgentSocketPath(os.Getpid()); got != path {
+		t.Errorf("AgentSocketPath = %q, want %q", got, path)
+	}
+
+	send := controlClient(t, dialUnix(t, path))
+	if resp := send("set debug"); resp != "ok" {
+		t.Errorf("set returned %q", resp)
+	}
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Errorf("level = %v, want %v", level, slog.LevelDebug)
+	}
+
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket still exists after stop: %v", err)
+	}
+}
+
+// TestStartAgentOpenDirectory verifies that the agent restricts an existing socket directory to the user
+func TestStartAgentOpenDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not enforced on Windows")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(AgentDirEnvVar, dir)
+
+	stop, err := StartAgent(&ControlServer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	fi, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o700 {
+		t.Errorf("directory mode is %v, want %v", perm, os.FileMode(0o700))
+	}
+}
+
+// TestStartAgentInUse verifies that the agent does not take over the socket of a running agent
+func TestStartAgentInUse(t *testing.T) {
+	t.Setenv(AgentDirEnvVar, t.TempDir())
+
+	stop, err := StartAgent(&ControlServer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	if stop, err := StartAgent(&ControlServer{}); !errors.Is(err, syscall.EADDRINUSE) {
+		if err == nil {
+			stop()
+		}
+		t.Fatalf("second StartAgent returned %v, want EADDRINUSE", err)
+	}
+}
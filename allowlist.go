@@ -0,0 +1,134 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DebugAllowlist is a runtime-updatable set of request or trace IDs for
+// which Debug is enabled, e.g. to debug a single stuck request. Entries
+// expire after the duration given when adding them. It is used by handlers
+// created with [WithDebugAllowlist].
+//
+// A DebugAllowlist is safe for concurrent use.
+type DebugAllowlist struct {
+	// now returns the current time.
+	now func() time.Time
+
+	size    atomic.Int64
+	mu      sync.RWMutex
+	entries map[string]time.Time // ID to expiry, zero for none
+}
+
+// NewDebugAllowlist creates an empty [DebugAllowlist].
+func NewDebugAllowlist() *DebugAllowlist {
+	return &DebugAllowlist{entries: make(map[string]time.Time)}
+}
+
+// Add adds id to the allowlist for ttl, or until removed if ttl is not
+// positive. Adding an ID again resets its expiry.
+func (a *DebugAllowlist) Add(id string, ttl time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var expiry time.Time
+	if ttl > 0 {
+		expiry = a.clock().Add(ttl)
+	}
+	a.entries[id] = expiry
+	a.prune()
+}
+
+// Remove removes id from the allowlist.
+func (a *DebugAllowlist) Remove(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.entries, id)
+	a.prune()
+}
+
+// IDs returns the IDs in the allowlist that have not expired, in order.
+func (a *DebugAllowlist) IDs() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.prune()
+	return slices.Sorted(maps.Keys(a.entries))
+}
+
+// contains reports whether id is in the allowlist and has not expired.
+func (a *DebugAllowlist) contains(id string) bool {
+	if a.size.Load() == 0 {
+		return false
+	}
+	a.mu.RLock()
+	expiry, ok := a.entries[id]
+	a.mu.RUnlock()
+	return ok && (expiry.IsZero() || a.clock().Before(expiry))
+}
+
+// prune removes expired entries. a.mu must be held.
+func (a *DebugAllowlist) prune() {
+	now := a.clock()
+	maps.DeleteFunc(a.entries, func(_ string, expiry time.Time) bool {
+		return !expiry.IsZero() && !now.Before(expiry)
+	})
+	a.size.Store(int64(len(a.entries)))
+}
+
+// clock returns the current time.
+func (a *DebugAllowlist) clock() time.Time {
+	if a.now != nil {
+		return a.now()
+	}
+	return time.Now()
+}
+
+// debugAllowlist is the configuration of [WithDebugAllowlist].
+type debugAllowlist struct {
+	list *DebugAllowlist
+	id   TraceIDFunc
+	key  string
+}
+
+// WithDebugAllowlist enables Debug for records of requests in list. The ID
+// of a record is given by id for its context, or by the top-level attribute
+// key of the record. Either may be left empty.
+//
+// Since attributes are only known in Handle, Enabled reports true for Debug
+// and above while list is not empty and key is set, and the final decision
+// is made in Handle.
+func WithDebugAllowlist(list *DebugAllowlist, id TraceIDFunc, key string) Option {
+	return func(o *options) {
+		o.allowlist = &debugAllowlist{list: list, id: id, key: key}
+	}
+}
+
+// contextAllowed reports whether the ID of ctx is in the allowlist.
+func (a *debugAllowlist) contextAllowed(ctx context.Context, level slog.Level) bool {
+	if level < slog.LevelDebug || a.id == nil || ctx == nil || a.list.size.Load() == 0 {
+		return false
+	}
+	id, ok := a.id(ctx)
+	return ok && a.list.contains(id)
+}
+
+// attrEnabled reports whether a record at level may pass due to its
+// attribute even though the regular threshold rejects it.
+func (a *debugAllowlist) attrEnabled(level slog.Level) bool {
+	return a.key != "" && level >= slog.LevelDebug && a.list.size.Load() > 0
+}
+
+// recordAllowed reports whether the ID attribute of record is in the
+// allowlist.
+func (h *OverrideHandler) recordAllowed(record slog.Record) bool {
+	a := h.opts.allowlist
+	if a == nil || !a.attrEnabled(record.Level) {
+		return false
+	}
+	value, ok := h.lookupAttr(record, a.key)
+	return ok && a.list.contains(value.Resolve().String())
+}
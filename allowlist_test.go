@@ -0,0 +1,48 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestDebugAllowlist verifies that Debug is enabled for allowlisted IDs until they expire
+func TestDebugAllowlist(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	now := time.Now()
+	list := NewDebugAllowlist()
+	list.now = func() time.Time { return now }
+	handler := NewWithLevel(assertHandler, slog.LevelInfo, WithDebugAllowlist(list, testTraceID, "request_id"))
+	logger := slog.New(handler)
+
+	stuckCtx := withTrace("stuck")
+	otherCtx := withTrace("other")
+
+	logger.DebugContext(stuckCtx, "debug before allowlisting")
+	list.Add("stuck", time.Minute)
+	list.Add("req-7", 0)
+	if got := list.IDs(); !slices.Equal(got, []string{"req-7", "stuck"}) {
+		t.Errorf("IDs = %v", got)
+	}
+
+	logger.DebugContext(stuckCtx, "debug of stuck trace")
+	logger.DebugContext(otherCtx, "debug of other trace")
+	logger.With("request_id", "req-7").Debug("debug of request")
+	logger.Debug("debug of other request", "request_id", "req-8")
+
+	now = now.Add(2 * time.Minute)
+	logger.DebugContext(stuckCtx, "debug after expiry")
+	list.Remove("req-7")
+	logger.Debug("debug after removal", "request_id", "req-7")
+	if got := list.IDs(); len(got) != 0 {
+		t.Errorf("IDs after expiry and removal = %v", got)
+	}
+
+	assertHandler.AssertMessage("debug of stuck trace")
+	assertHandler.AssertMessage("debug of request")
+}
@@ -0,0 +1,103 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// asyncQueue forwards records to the underlying handler from a worker
+// goroutine.
+type asyncQueue struct {
+	records   chan asyncRecord
+	highWater int
+	level     slog.Level
+
+	start sync.Once
+	done  chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// asyncRecord is a record queued by Handle along with the handler that
+// received it.
+type asyncRecord struct {
+	h      *OverrideHandler
+	ctx    context.Context
+	record slog.Record
+}
+
+// WithAsync makes Handle queue records, up to size, and forward them to the
+// underlying handler from a worker goroutine, so that logging calls do not
+// wait on slow sinks.
+//
+// Once highWater records are queued, records below level are suppressed
+// until the queue drains below highWater again, shedding verbose records
+// instead of blocking. Records at level or above are never dropped: if the
+// queue is full, Handle waits for room. Errors of the underlying handler
+// are only visible through [WithHandleErrors]; Handle returns nil once the
+// record is queued. Call [OverrideHandler.Close] to forward the queued
+// records and stop the worker.
+func WithAsync(size, highWater int, level slog.Level) Option {
+	return func(o *options) {
+		o.async = &asyncQueue{
+			records:   make(chan asyncRecord, max(size, 1)),
+			highWater: highWater,
+			level:     level,
+			done:      make(chan struct{}),
+		}
+	}
+}
+
+// sheds reports whether records at level are currently suppressed because
+// the queue is above the high-water mark.
+func (q *asyncQueue) sheds(level slog.Level) bool {
+	return level < q.level && len(q.records) >= q.highWater
+}
+
+// enqueue queues record for h, or forwards it right away if the queue was
+// closed.
+func (q *asyncQueue) enqueue(ctx context.Context, h *OverrideHandler, record slog.Record) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return h.forward(ctx, record)
+	}
+	q.start.Do(func() { go q.run() })
+	q.records <- asyncRecord{h: h, ctx: context.WithoutCancel(ctx), record: record.Clone()}
+	return nil
+}
+
+// run forwards queued records until the queue is closed.
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	for r := range q.records {
+		_ = r.h.forward(r.ctx, r.record)
+	}
+}
+
+// close forwards the queued records and stops the worker.
+func (q *asyncQueue) close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.records)
+	q.mu.Unlock()
+
+	q.start.Do(func() { go q.run() })
+	<-q.done
+}
+
+// Close forwards the records queued with [WithAsync] and stops the worker,
+// after which records are forwarded synchronously. It affects every handler
+// derived from the same handler and does nothing without WithAsync.
+func (h *OverrideHandler) Close() error {
+	if h.opts.async != nil {
+		h.opts.async.close()
+	}
+	return nil
+}
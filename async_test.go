@@ -0,0 +1,61 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// gatedHandler signals entered and blocks in Handle until the gate is
+// closed.
+type gatedHandler struct {
+	slog.Handler
+	entered chan struct{}
+	gate    chan struct{}
+}
+
+func (h *gatedHandler) Handle(ctx context.Context, record slog.Record) error {
+	select {
+	case h.entered <- struct{}{}:
+	default:
+	}
+	<-h.gate
+	return h.Handler.Handle(ctx, record)
+}
+
+// TestAsync verifies queued forwarding and shedding above the high-water mark
+func TestAsync(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	basic := &gatedHandler{
+		Handler: assertHandler,
+		entered: make(chan struct{}, 1),
+		gate:    make(chan struct{}),
+	}
+	handler := NewWithLevel(basic, slog.LevelDebug, WithAsync(8, 2, slog.LevelWarn))
+	logger := slog.New(handler)
+
+	// The worker takes the first record and blocks on the gate
+	logger.Info("first")
+	<-basic.entered
+	logger.Info("queued")
+	logger.Debug("queued debug")
+	if handler.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Info should be shed above the high-water mark")
+	}
+	logger.Info("shed")
+	logger.Error("error above high water")
+
+	close(basic.gate)
+	if err := handler.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	logger.Info("after close")
+
+	for _, msg := range []string{"first", "queued", "queued debug", "error above high water", "after close"} {
+		assertHandler.AssertMessage(msg)
+	}
+}
@@ -0,0 +1,70 @@
+package slogleveloverride
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// BaggageLevelKey is the key of the W3C Baggage entry carrying the level
+// of a request across services, e.g. "baggage: loglevel=debug".
+const BaggageLevelKey = "loglevel"
+
+// BaggageMiddleware wraps next so that the level of a [BaggageLevelKey]
+// entry in the baggage header of incoming requests is set on the request
+// context with [ContextWithLevel]. Handlers created with
+// [WithContextLevels] then honor it for records logged with the context.
+//
+// Since any client can set the header, only install the middleware where
+// callers are trusted to raise the verbosity, e.g. behind a gateway
+// stripping baggage from outside traffic.
+func BaggageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if text, ok := baggageValue(r.Header, BaggageLevelKey); ok {
+			if level, err := parseLevel(text); err == nil {
+				r = r.WithContext(ContextWithLevel(r.Context(), level))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SetBaggageLevel adds the level carried by the context of req, if any, to
+// its baggage header, replacing any [BaggageLevelKey] entry, so that the
+// decision propagates to the services req is sent to.
+func SetBaggageLevel(req *http.Request) {
+	level, ok := LevelFromContext(req.Context())
+	if !ok {
+		return
+	}
+	members := []string{BaggageLevelKey + "=" + url.PathEscape(strings.ToLower(level.String()))}
+	for _, header := range req.Header.Values("Baggage") {
+		for member := range strings.SplitSeq(header, ",") {
+			if key, _ := baggageMember(member); key != BaggageLevelKey && strings.TrimSpace(member) != "" {
+				members = append(members, strings.TrimSpace(member))
+			}
+		}
+	}
+	req.Header.Set("Baggage", strings.Join(members, ","))
+}
+
+// baggageValue returns the decoded value of the baggage entry key in h.
+func baggageValue(h http.Header, key string) (string, bool) {
+	for _, header := range h.Values("Baggage") {
+		for member := range strings.SplitSeq(header, ",") {
+			if k, value := baggageMember(member); k == key {
+				decoded, err := url.PathUnescape(value)
+				return decoded, err == nil
+			}
+		}
+	}
+	return "", false
+}
+
+// baggageMember splits a baggage list member into its key and value,
+// dropping its properties.
+func baggageMember(member string) (key, value string) {
+	member, _, _ = strings.Cut(member, ";")
+	key, value, _ = strings.Cut(member, "=")
+	return strings.TrimSpace(key), strings.TrimSpace(value)
+}
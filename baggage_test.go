@@ -0,0 +1,57 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestBaggageMiddleware verifies that the baggage level enables verbose records for the request only
+func TestBaggageMiddleware(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	logger := slog.New(NewWithLevel(assertHandler, slog.LevelInfo, WithContextLevels()))
+	handler := BaggageMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.DebugContext(r.Context(), "debug message", "path", r.URL.Path)
+	}))
+
+	for path, baggage := range map[string]string{
+		"/traced":  "userId=alice, loglevel=debug;ttl=1",
+		"/plain":   "userId=alice",
+		"/invalid": "loglevel=loud",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Baggage", baggage)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	logger.Debug("debug without context")
+
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "debug message",
+		Level:   slog.LevelDebug,
+		Attrs:   map[string]any{"path": "/traced"},
+	})
+}
+
+// TestSetBaggageLevel verifies that the context level replaces the baggage entry of outgoing requests
+func TestSetBaggageLevel(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Baggage", "loglevel=info, userId=alice")
+	SetBaggageLevel(req)
+	if got := req.Header.Get("Baggage"); got != "loglevel=info, userId=alice" {
+		t.Errorf("baggage without context level = %q", got)
+	}
+
+	req = req.WithContext(ContextWithLevel(req.Context(), slog.LevelDebug+2))
+	SetBaggageLevel(req)
+	if got := req.Header.Get("Baggage"); got != "loglevel=debug+2,userId=alice" {
+		t.Errorf("baggage = %q", got)
+	}
+	if text, _ := baggageValue(req.Header, BaggageLevelKey); text != "debug+2" {
+		t.Errorf("decoded level = %q", text)
+	}
+}
@@ -0,0 +1,46 @@
+package slogleveloverride
+
+import "log/slog"
+
+// Sources recorded for the changes made by this package, reported by
+// [OverrideHandler.LayerSource] and [OverrideHandler.EffectiveLevel] so that
+// operators can tell why a handler is at a given level. Layers set by
+// maintenance windows, schedules, remote sources, panics and shutdown are
+// recorded with the name of their layer.
+const (
+	// SourceCode is the source of changes made by calling the API.
+	SourceCode = "code"
+	// SourceHTTP is the source of changes made through [LevelEndpoint]
+	// and [ActuatorHandler].
+	SourceHTTP = "http-admin"
+	// SourceControl is the source of changes made through [ControlServer].
+	SourceControl = "control"
+	// SourceSignal is the source of changes made on SIGHUP by
+	// [ReloadOnHangup] from the environment.
+	SourceSignal = "sighup"
+	// SourceFlag is the source of changes made through [LevelValue] and
+	// [SpecValue].
+	SourceFlag = "flag"
+)
+
+// FileSource returns the source of changes read from the file at path, such
+// as "file:/etc/app/log.level".
+func FileSource(path string) string {
+	return "file:" + path
+}
+
+// SetLevelFrom is like [OverrideHandler.SetLevel], recording source, such
+// as "deploy-hook" or [FileSource] of a configuration file, as the source
+// of the change.
+func (h *OverrideHandler) SetLevelFrom(source string, newLevel slog.Leveler) {
+	_ = h.setLevel("", source, newLevel)
+}
+
+// LayerSource returns the source of the override of layer, or false if
+// layer has none.
+func (h *OverrideHandler) LayerSource(layer Layer) (string, bool) {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	source, ok := h.state.sources[layer]
+	return source, ok
+}
@@ -0,0 +1,52 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestLayerSource verifies that the source of every layer is recorded and cleared with it
+func TestLayerSource(t *testing.T) {
+	handler := New(slog.DiscardHandler)
+	if _, ok := handler.LayerSource(LayerBase); ok {
+		t.Error("LayerSource reports a source without override")
+	}
+
+	handler.SetLevelFrom(FileSource("/etc/app/log.level"), slog.LevelDebug)
+	handler.SetLayerLevel(LayerEmergency, slog.LevelWarn)
+	if source, _ := handler.LayerSource(LayerBase); source != "file:/etc/app/log.level" {
+		t.Errorf("base source = %q", source)
+	}
+	if _, origin := handler.EffectiveLevel(); origin.Source != SourceCode {
+		t.Errorf("origin = %v, want the emergency layer set by code", origin)
+	}
+
+	handler.ClearLayer(LayerEmergency)
+	if _, origin := handler.EffectiveLevel(); origin.String() != "override at layer base from file:/etc/app/log.level" {
+		t.Errorf("origin = %q", origin)
+	}
+	handler.ClearLevel()
+	if _, ok := handler.LayerSource(LayerBase); ok {
+		t.Error("LayerSource reports a source after ClearLevel")
+	}
+}
+
+// TestLevelEndpointSource verifies that changes through the endpoint are attributed to it
+func TestLevelEndpointSource(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	endpoint := &LevelEndpoint{Handler: handler}
+
+	put := httptest.NewRecorder()
+	endpoint.ServeHTTP(put, httptest.NewRequest(http.MethodPut, "/-/loglevel", strings.NewReader("debug")))
+	if put.Code != http.StatusNoContent {
+		t.Fatalf("PUT status = %d", put.Code)
+	}
+	get := httptest.NewRecorder()
+	endpoint.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/-/loglevel", nil))
+	if got := get.Header().Get("X-Level-Origin"); got != "override at layer base from http-admin" {
+		t.Errorf("X-Level-Origin = %q", got)
+	}
+}
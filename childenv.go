@@ -0,0 +1,39 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+// LevelEnvVar is the environment variable read by [ReloadOnHangup] and
+// written by [ExportLevel].
+const LevelEnvVar = "LOG_LEVEL"
+
+// ExportLevel sets the environment variable name, or [LevelEnvVar] if name
+// is empty, to the effective level of h, and updates it whenever the
+// override of h changes, so that subprocesses started later with os/exec
+// inherit the current verbosity of the parent. Calling stop ends the
+// updates and leaves the variable as it is.
+//
+// Since the environment is process-wide, at most one handler should be
+// exported under each name. To pass the level to a single command instead,
+// append the result of [LevelEnv] to its Env.
+func ExportLevel(h *OverrideHandler, name string) (stop func(), err error) {
+	if name == "" {
+		name = LevelEnvVar
+	}
+	if err := os.Setenv(name, h.effectiveLevel(context.Background()).String()); err != nil {
+		return nil, err
+	}
+	stop = h.state.watch(func(slog.Leveler) {
+		_ = os.Setenv(name, h.effectiveLevel(context.Background()).String())
+	})
+	return stop, nil
+}
+
+// LevelEnv returns the effective level of h as an environment entry of
+// [LevelEnvVar], such as "LOG_LEVEL=DEBUG", for the Env of an exec.Cmd.
+func LevelEnv(h *OverrideHandler) string {
+	return LevelEnvVar + "=" + h.effectiveLevel(context.Background()).String()
+}
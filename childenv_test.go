@@ -0,0 +1,34 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+)
+
+// TestExportLevel verifies that the environment follows the level of the handler until stopped
+func TestExportLevel(t *testing.T) {
+	t.Setenv("TEST_LOG_LEVEL", "")
+	handler := New(slog.NewTextHandler(nil, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	stop, err := ExportLevel(handler, "TEST_LOG_LEVEL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv("TEST_LOG_LEVEL"); got != "WARN" {
+		t.Errorf("exported %q before any override, want WARN", got)
+	}
+	handler.SetLevel(slog.LevelDebug)
+	if got := os.Getenv("TEST_LOG_LEVEL"); got != "DEBUG" {
+		t.Errorf("exported %q after SetLevel, want DEBUG", got)
+	}
+	if got := LevelEnv(handler); got != "LOG_LEVEL=DEBUG" {
+		t.Errorf("LevelEnv = %q", got)
+	}
+
+	stop()
+	handler.ClearLevel()
+	if got := os.Getenv("TEST_LOG_LEVEL"); got != "DEBUG" {
+		t.Errorf("exported %q after stop, want DEBUG", got)
+	}
+}
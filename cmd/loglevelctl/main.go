@@ -0,0 +1,199 @@
+// Command loglevelctl gets and sets log levels of running processes through
+// the control protocol served by slogleveloverride.ControlServer.
+//
+// Usage:
+//
+//	loglevelctl [-socket path | -addr host:port | -pid pid] [-token token] <command> [args]
+//
+// With -pid, the socket of the agent started with
+// slogleveloverride.StartAgent in that process is used.
+//
+// Commands:
+//
+//	get [scope]                 print the level of the handler or of a scope
+//	list                        print the levels of all registered scopes
+//	origin [scope]              print the effective level and what set it
+//	set [-for 10m] <level|spec> set a level or a spec such as "db=debug"
+//	clear [scope]               remove an override
+//	dump [scope]                print the suppressed records kept in memory
+//	format [text|json]          print or switch the output format
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	slogleveloverride "github.com/martin-viggiano/slog-level-override"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes loglevelctl with args and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("loglevelctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	socket := fs.String("socket", "", "unix socket of the control server")
+	addr := fs.String("addr", "", "TCP address of the control server")
+	pid := fs.Int("pid", 0, "process ID of a process running the agent")
+	token := fs.String("token", os.Getenv("LOGLEVELCTL_TOKEN"), "authentication token (default $LOGLEVELCTL_TOKEN)")
+	timeout := fs.Duration("timeout", 5*time.Second, "timeout of the whole exchange")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cmd, err := command(fs.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, "loglevelctl:", err)
+		return 2
+	}
+
+	network, address := "unix", *socket
+	switch targets := countSet(*socket != "", *addr != "", *pid != 0); {
+	case targets > 1:
+		fmt.Fprintln(stderr, "loglevelctl: -socket, -addr and -pid are mutually exclusive")
+		return 2
+	case targets == 0:
+		fmt.Fprintln(stderr, "loglevelctl: one of -socket, -addr or -pid is required")
+		return 2
+	case *addr != "":
+		network, address = "tcp", *addr
+	case *pid != 0:
+		if address, err = slogleveloverride.AgentSocketPath(*pid); err != nil {
+			fmt.Fprintln(stderr, "loglevelctl:", err)
+			return 1
+		}
+	}
+
+	resp, err := exchange(network, address, *token, cmd, *timeout)
+	if err != nil {
+		fmt.Fprintln(stderr, "loglevelctl:", err)
+		return 1
+	}
+	if resp != "" {
+		fmt.Fprintln(stdout, resp)
+	}
+	return 0
+}
+
+// countSet returns the number of conditions that hold.
+func countSet(conds ...bool) int {
+	n := 0
+	for _, c := range conds {
+		if c {
+			n++
+		}
+	}
+	return n
+}
+
+// command translates command line arguments into a control protocol command.
+func command(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("missing command")
+	}
+	switch name, rest := args[0], args[1:]; name {
+	case "get", "clear", "dump", "origin":
+		if len(rest) > 1 {
+			return "", fmt.Errorf("%s takes at most one scope", name)
+		}
+		return strings.TrimSpace(name + " " + strings.Join(rest, "")), nil
+	case "format":
+		if len(rest) > 1 {
+			return "", errors.New("format takes at most one format")
+		}
+		return strings.TrimSpace(name + " " + strings.Join(rest, "")), nil
+	case "list":
+		if len(rest) > 0 {
+			return "", errors.New("list takes no arguments")
+		}
+		return name, nil
+	case "set":
+		fs := flag.NewFlagSet("set", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		duration := fs.Duration("for", 0, "revert the change after this duration")
+		if err := fs.Parse(rest); err != nil {
+			return "", err
+		}
+		if fs.NArg() != 1 {
+			return "", errors.New("set takes exactly one level or spec")
+		}
+		cmd := "set " + fs.Arg(0)
+		if *duration > 0 {
+			cmd += " for " + duration.String()
+		}
+		return cmd, nil
+	default:
+		return "", fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// exchange sends cmd to the control server and returns the payload of its
+// response.
+func exchange(network, address, token, cmd string, timeout time.Duration) (string, error) {
+	conn, err := net.DialTimeout(network, address, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return "", err
+	}
+
+	if token != "" {
+		if _, err := fmt.Fprintf(conn, "auth %s\n", token); err != nil {
+			return "", err
+		}
+	}
+	if _, err := fmt.Fprintf(conn, "%s\nquit\n", cmd); err != nil {
+		return "", err
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\n")
+	if msg, found := strings.CutPrefix(line, "error "); found {
+		return "", errors.New(msg)
+	}
+	if line == "ok" {
+		return "", nil
+	}
+	payload, found := strings.CutPrefix(line, "ok ")
+	if !found {
+		return "", fmt.Errorf("unexpected response %q", line)
+	}
+	if strings.HasPrefix(cmd, "dump") {
+		return readLines(reader, payload)
+	}
+	return payload, nil
+}
+
+// readLines reads the number of lines announced by count, the payload of a
+// dump response, and returns them joined.
+func readLines(reader *bufio.Reader, count string) (string, error) {
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return "", fmt.Errorf("unexpected line count %q", count)
+	}
+	lines := make([]string, n)
+	for i := range lines {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		lines[i] = strings.TrimSuffix(line, "\n")
+	}
+	return strings.Join(lines, "\n"), nil
+}
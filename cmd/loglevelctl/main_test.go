@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	slogleveloverride "github.com/martin-viggiano/slog-level-override"
+)
+
+// TestCommand verifies the translation of arguments into protocol commands
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"get"}, "get"},
+		{[]string{"get", "db"}, "get db"},
+		{[]string{"list"}, "list"},
+		{[]string{"set", "debug"}, "set debug"},
+		{[]string{"set", "-for", "10m", "db=debug"}, "set db=debug for 10m0s"},
+		{[]string{"clear", "db"}, "clear db"},
+		{[]string{"dump"}, "dump"},
+		{[]string{"origin", "db"}, "origin db"},
+		{[]string{"format", "json"}, "format json"},
+	}
+	for _, tt := range tests {
+		got, err := command(tt.args)
+		if err != nil || got != tt.want {
+			t.Errorf("command(%q) = (%q, %v), want %q", tt.args, got, err, tt.want)
+		}
+	}
+
+	for _, invalid := range [][]string{nil, {"set"}, {"get", "a", "b"}, {"format", "a", "b"}, {"reboot"}} {
+		if _, err := command(invalid); err == nil {
+			t.Errorf("command(%q) should return an error", invalid)
+		}
+	}
+}
+
+// TestRun verifies an end-to-end exchange with a control server
+func TestRun(t *testing.T) {
+	handler := slogleveloverride.New(slog.DiscardHandler)
+	server := &slogleveloverride.ControlServer{Handler: handler, Token: "s3cret"}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	go server.Serve(l)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	base := []string{"-addr", l.Addr().String(), "-token", "s3cret"}
+
+	if code := run(append(base, "set", "warn"), &stdout, &stderr); code != 0 {
+		t.Fatalf("set exited with %d: %s", code, stderr.String())
+	}
+	if code := run(append(base, "get"), &stdout, &stderr); code != 0 {
+		t.Fatalf("get exited with %d: %s", code, stderr.String())
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "WARN" {
+		t.Fatalf("get printed %q, want %q", got, "WARN")
+	}
+
+	stderr.Reset()
+	if code := run(append(base, "get", "db"), &stdout, &stderr); code != 1 {
+		t.Fatalf("get db exited with %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "no registry") {
+		t.Fatalf("get db printed %q", stderr.String())
+	}
+}
+
+// TestRunDump verifies that dump prints every record line
+func TestRunDump(t *testing.T) {
+	handler := slogleveloverride.NewWithLevel(slog.DiscardHandler, slog.LevelInfo, slogleveloverride.WithSuppressedBuffer(8, 0))
+	server := &slogleveloverride.ControlServer{Handler: handler}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	go server.Serve(l)
+	defer server.Close()
+
+	logger := slog.New(handler)
+	logger.Debug("first")
+	logger.Debug("second")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-addr", l.Addr().String(), "dump"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("dump exited with %d: %s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "msg=first") || !strings.Contains(lines[1], "msg=second") {
+		t.Fatalf("dump printed %q", stdout.String())
+	}
+}
+
+// TestRunPid verifies that -pid reaches the agent of a process
+func TestRunPid(t *testing.T) {
+	t.Setenv(slogleveloverride.AgentDirEnvVar, t.TempDir())
+	handler := slogleveloverride.NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	stop, err := slogleveloverride.StartAgent(&slogleveloverride.ControlServer{Handler: handler})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-pid", strconv.Itoa(os.Getpid()), "get"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("get exited with %d: %s", code, stderr.String())
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "WARN" {
+		t.Errorf("get printed %q, want %q", got, "WARN")
+	}
+	if code := run([]string{"-pid", "1", "-addr", "localhost:1", "get"}, &stdout, &stderr); code != 2 {
+		t.Errorf("-pid with -addr exited with %d, want 2", code)
+	}
+}
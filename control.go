@@ -0,0 +1,518 @@
+package slogleveloverride
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ControlServer serves a line-based text protocol to inspect and change
+// levels at runtime, e.g. over a unix socket for local operators and
+// sidecars.
+//
+// Each request is a single line and is answered with a line starting with
+// "ok" or "error". The supported commands are:
+//
+//	get              level of Handler
+//	get <scope>      level of the handler registered under scope
+//	list             levels of all registered handlers, as a spec
+//	origin [scope]   effective level of Handler or of a registered
+//	                 handler and what set it, e.g.
+//	                 "DEBUG override at layer base from control"
+//	set <level>      set the level of Handler
+//	set <spec>       apply a spec such as "db=debug,http=warn" to Registry
+//	set <...> for <duration>
+//	                 set temporarily, restoring the previous overrides
+//	                 once the duration, e.g. "10m", has elapsed, unless
+//	                 they were changed again meanwhile
+//	clear            remove the override of Handler
+//	clear <scope>    remove the override of a registered handler
+//	dump             records kept by WithSuppressedBuffer in Handler
+//	dump <scope>     records kept by a registered handler
+//	format           output format of Formats
+//	format <format>  switch the output of Formats to text or json
+//	quit             close the connection
+//
+// Unset overrides are reported as "unset". The response of dump is
+// "ok <n>", followed by n lines holding one record each.
+//
+// When Token is set, the first line of every connection must be
+// "auth <token>"; connections failing to authenticate are closed.
+// Connections accepted by Serve are closed if the authentication line or a
+// command does not arrive in time, see AuthTimeout and IdleTimeout.
+type ControlServer struct {
+	// Handler is the target of commands without a scope. It may be nil.
+	Handler *OverrideHandler
+	// Registry is the target of scoped commands. It may be nil.
+	Registry *Registry
+	// Formats is the target of format commands. It may be nil.
+	Formats *FormatSwitcher
+
+	// Token, if not empty, must be presented by clients before any command.
+	Token string
+	// AllowedNetworks, if not empty, restricts TCP clients to the given
+	// networks. It does not apply to unix socket clients.
+	AllowedNetworks []netip.Prefix
+
+	// AuthTimeout bounds the time a connection accepted by Serve has to
+	// send its first line, the authentication line if Token is set. Zero
+	// means [DefaultControlAuthTimeout].
+	AuthTimeout time.Duration
+	// IdleTimeout bounds the time a connection accepted by Serve may stay
+	// idle between commands. Zero means [DefaultControlIdleTimeout].
+	IdleTimeout time.Duration
+
+	mu        sync.Mutex
+	listeners []net.Listener
+	conns     map[net.Conn]struct{}
+	restores  map[*time.Timer]struct{}
+}
+
+const (
+	// DefaultControlAuthTimeout is the AuthTimeout of a [ControlServer]
+	// that does not set one.
+	DefaultControlAuthTimeout = 10 * time.Second
+	// DefaultControlIdleTimeout is the IdleTimeout of a [ControlServer]
+	// that does not set one.
+	DefaultControlIdleTimeout = 5 * time.Minute
+)
+
+// ErrServerClosed is returned by [ControlServer.Serve] after the server has
+// been closed.
+var ErrServerClosed = errors.New("slogleveloverride: control server closed")
+
+// ListenAndServeUnix listens on the unix socket at path and serves requests
+// until the server is closed. A stale socket file left at path by a previous
+// process is removed first; if a server still accepts connections on it,
+// an error wrapping [syscall.EADDRINUSE] is returned instead.
+func (s *ControlServer) ListenAndServeUnix(path string) error {
+	l, err := listenUnix(path)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// listenUnix listens on the unix socket at path, removing the socket file
+// first if no server accepts connections on it any more.
+func listenUnix(path string) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil && fi.Mode().Type() == fs.ModeSocket {
+		conn, err := net.DialTimeout("unix", path, time.Second)
+		switch {
+		case err == nil:
+			conn.Close()
+			return nil, fmt.Errorf("slogleveloverride: %s: %w", path, syscall.EADDRINUSE)
+		case errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, fs.ErrNotExist):
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("slogleveloverride: %s: %w: %w", path, syscall.EADDRINUSE, err)
+		}
+	}
+	return net.Listen("unix", path)
+}
+
+// ListenAndServeTCP listens on the TCP address addr and serves requests
+// until the server is closed. Exposing the server beyond localhost should
+// be paired with Token and AllowedNetworks.
+func (s *ControlServer) ListenAndServeTCP(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on l and serves each of them in its own
+// goroutine until the server is closed or l fails.
+func (s *ControlServer) Serve(l net.Listener) error {
+	s.mu.Lock()
+	s.listeners = append(s.listeners, l)
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return ErrServerClosed
+			}
+			return err
+		}
+		if !s.allowed(conn.RemoteAddr()) {
+			conn.Close()
+			continue
+		}
+		s.track(conn, true)
+		go func() {
+			defer s.track(conn, false)
+			defer conn.Close()
+			s.serveStream(conn, conn, func(first bool) {
+				timeout := cmp.Or(s.IdleTimeout, DefaultControlIdleTimeout)
+				if first {
+					timeout = cmp.Or(s.AuthTimeout, DefaultControlAuthTimeout)
+				}
+				_ = conn.SetReadDeadline(time.Now().Add(timeout))
+			})
+		}()
+	}
+}
+
+// track adds conn to the connections closed by Close, or removes it.
+func (s *ControlServer) track(conn net.Conn, add bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.conns, conn)
+		return
+	}
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]struct{})
+	}
+	s.conns[conn] = struct{}{}
+}
+
+// allowed reports whether a client connecting from addr may be served.
+func (s *ControlServer) allowed(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok || len(s.AllowedNetworks) == 0 {
+		return true
+	}
+	ip, ok := netip.AddrFromSlice(tcpAddr.IP)
+	if !ok {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, network := range s.AllowedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Close closes all listeners passed to Serve and the connections they
+// accepted. Pending restores of temporary set commands are canceled, leaving
+// the temporary overrides in place.
+func (s *ControlServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for _, l := range s.listeners {
+		if err := l.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+	s.listeners = nil
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+	for t := range s.restores {
+		t.Stop()
+	}
+	s.restores = nil
+	return errors.Join(errs...)
+}
+
+// ServeStream executes the commands read from r, writing responses to w,
+// until r is exhausted or a quit command is received. It serves the
+// connections accepted by Serve, and can serve any other stream, such as
+// the pipes set up by [StartWithControl].
+func (s *ControlServer) ServeStream(r io.Reader, w io.Writer) error {
+	return s.serveStream(r, w, nil)
+}
+
+// serveStream implements ServeStream, calling beforeRead, if not nil, before
+// reading each line, with first set for the first line.
+func (s *ControlServer) serveStream(r io.Reader, w io.Writer, beforeRead func(first bool)) error {
+	if beforeRead == nil {
+		beforeRead = func(bool) {}
+	}
+	scanner := bufio.NewScanner(r)
+	beforeRead(true)
+	if s.Token != "" {
+		if !s.authenticate(scanner) {
+			_, err := io.WriteString(w, "error unauthorized\n")
+			return err
+		}
+		beforeRead(false)
+	}
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "quit" {
+			return nil
+		}
+		resp, err := s.execute(line)
+		if err != nil {
+			resp = "error " + err.Error()
+		} else {
+			resp = strings.TrimSpace("ok " + resp)
+		}
+		if _, err := io.WriteString(w, resp+"\n"); err != nil {
+			return err
+		}
+		beforeRead(false)
+	}
+	return scanner.Err()
+}
+
+// authenticate reads the first line of the stream and checks it presents
+// the expected token.
+func (s *ControlServer) authenticate(scanner *bufio.Scanner) bool {
+	if !scanner.Scan() {
+		return false
+	}
+	token, found := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "auth ")
+	return found && subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) == 1
+}
+
+// execute runs a single command line and returns the response payload.
+func (s *ControlServer) execute(line string) (string, error) {
+	cmd, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch cmd {
+	case "get":
+		h, err := s.target(arg)
+		if err != nil {
+			return "", err
+		}
+		return formatOverride(h), nil
+	case "origin":
+		h, err := s.target(arg)
+		if err != nil {
+			return "", err
+		}
+		level, origin := h.EffectiveLevel()
+		return LevelName(level) + " " + origin.String(), nil
+	case "list":
+		if s.Registry == nil {
+			return "", errors.New("no registry")
+		}
+		entries := make([]string, 0)
+		for _, name := range s.Registry.Names() {
+			if h, ok := s.Registry.Handler(name); ok {
+				entries = append(entries, name+"="+formatOverride(h))
+			}
+		}
+		return strings.Join(entries, ","), nil
+	case "set":
+		return "", s.set(arg)
+	case "clear":
+		h, err := s.target(arg)
+		if err != nil {
+			return "", err
+		}
+		h.ClearLevel()
+		return "", nil
+	case "dump":
+		h, err := s.target(arg)
+		if err != nil {
+			return "", err
+		}
+		var buf strings.Builder
+		if err := h.DumpSuppressed(&buf); err != nil {
+			return "", err
+		}
+		lines := strings.TrimSuffix(buf.String(), "\n")
+		if lines == "" {
+			return "0", nil
+		}
+		return fmt.Sprintf("%d\n%s", strings.Count(lines, "\n")+1, lines), nil
+	case "format":
+		if s.Formats == nil {
+			return "", errors.New("no format switcher")
+		}
+		if arg == "" {
+			return s.Formats.Format(), nil
+		}
+		return "", s.Formats.SetFormat(arg)
+	default:
+		return "", fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+// set executes the arguments of a set command.
+func (s *ControlServer) set(arg string) error {
+	arg, durationText, temporary := strings.Cut(arg, " for ")
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return errors.New("missing level")
+	}
+	var duration time.Duration
+	if temporary {
+		var err error
+		if duration, err = time.ParseDuration(strings.TrimSpace(durationText)); err != nil {
+			return err
+		}
+		if duration <= 0 {
+			return fmt.Errorf("invalid duration %q", durationText)
+		}
+	}
+
+	// apply makes the change and returns the generation of the change of
+	// each target, read along with it.
+	var (
+		targets []*OverrideHandler
+		apply   func() (map[*OverrideHandler]uint64, error)
+	)
+	if !strings.Contains(arg, "=") {
+		level, err := parseLevel(arg)
+		if err != nil {
+			return err
+		}
+		h, err := s.target("")
+		if err != nil {
+			return err
+		}
+		targets = []*OverrideHandler{h}
+		apply = func() (map[*OverrideHandler]uint64, error) {
+			if err := checkPolicy("", level); err != nil {
+				return nil, err
+			}
+			return map[*OverrideHandler]uint64{h: h.storeLevel(SourceControl, level)}, nil
+		}
+	} else {
+		if s.Registry == nil {
+			return errors.New("no registry")
+		}
+		spec, err := ParseSpec(arg)
+		if err != nil {
+			return err
+		}
+		for _, name := range s.Registry.Names() {
+			if h, ok := s.Registry.Handler(name); ok {
+				if _, ok := spec.Lookup(name); ok {
+					targets = append(targets, h)
+				}
+			}
+		}
+		apply = func() (map[*OverrideHandler]uint64, error) {
+			return s.Registry.applySpec(spec, SourceControl)
+		}
+	}
+
+	if !temporary {
+		_, err := apply()
+		return err
+	}
+	saved := make([]savedOverride, len(targets))
+	for i, h := range targets {
+		saved[i] = saveOverride(h)
+	}
+	gens, err := apply()
+	if err != nil {
+		return err
+	}
+	for i := range saved {
+		saved[i].gen = gens[saved[i].h]
+	}
+	s.scheduleRestore(duration, saved)
+	return nil
+}
+
+// scheduleRestore restores the saved overrides once duration has elapsed,
+// unless the server is closed first.
+func (s *ControlServer) scheduleRestore(duration time.Duration, saved []savedOverride) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var t *time.Timer
+	t = time.AfterFunc(duration, func() {
+		s.mu.Lock()
+		_, pending := s.restores[t]
+		delete(s.restores, t)
+		s.mu.Unlock()
+		if !pending {
+			return
+		}
+		for _, o := range saved {
+			o.restore()
+		}
+	})
+	if s.restores == nil {
+		s.restores = make(map[*time.Timer]struct{})
+	}
+	s.restores[t] = struct{}{}
+}
+
+// savedOverride is the base override of a handler saved by a temporary set
+// command, with the generation the temporary override left.
+type savedOverride struct {
+	h       *OverrideHandler
+	leveler slog.Leveler
+	source  string
+	gen     uint64
+}
+
+// saveOverride saves the current base override of h and its source.
+func saveOverride(h *OverrideHandler) savedOverride {
+	leveler, _ := h.LayerLeveler(LayerBase)
+	source, _ := h.LayerSource(LayerBase)
+	return savedOverride{h: h, leveler: leveler, source: source}
+}
+
+// restore restores the saved override, unless the override of the handler
+// changed since the temporary override was set.
+func (o savedOverride) restore() {
+	if _, ok := o.h.state.compareAndSetLayer(LayerBase, o.gen, o.source, o.leveler); ok {
+		o.h.checkMismatch(context.Background())
+	}
+}
+
+// restoreFunc returns a function restoring the current base override of h
+// and its source.
+func restoreFunc(h *OverrideHandler) func() {
+	leveler, ok := h.LayerLeveler(LayerBase)
+	source, _ := h.LayerSource(LayerBase)
+	return func() {
+		if ok {
+			h.storeLevel(source, leveler)
+		} else {
+			h.ClearLevel()
+		}
+	}
+}
+
+// target returns the handler addressed by scope, or Handler if scope is empty.
+func (s *ControlServer) target(scope string) (*OverrideHandler, error) {
+	if scope == "" {
+		if s.Handler == nil {
+			return nil, errors.New("no handler")
+		}
+		return s.Handler, nil
+	}
+	if s.Registry == nil {
+		return nil, errors.New("no registry")
+	}
+	h, ok := s.Registry.Handler(scope)
+	if !ok {
+		return nil, fmt.Errorf("unknown scope %q", scope)
+	}
+	return h, nil
+}
+
+// formatOverride returns the current override level of h, or "unset".
+func formatOverride(h *OverrideHandler) string {
+	level, ok := h.Level()
+	if !ok {
+		return "unset"
+	}
+	return LevelName(level)
+}
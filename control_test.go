@@ -0,0 +1,354 @@
+package slogleveloverride
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// controlClient sends a command to a control connection and returns the response line
+func controlClient(t *testing.T, conn net.Conn) func(string) string {
+	t.Helper()
+	reader := bufio.NewReader(conn)
+	return func(cmd string) string {
+		t.Helper()
+		if _, err := fmt.Fprintln(conn, cmd); err != nil {
+			t.Fatalf("writing %q: %v", cmd, err)
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading response to %q: %v", cmd, err)
+		}
+		return strings.TrimSuffix(line, "\n")
+	}
+}
+
+// dialUnix waits for the control server to listen on path and connects to it
+func dialUnix(t *testing.T, path string) net.Conn {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			t.Cleanup(func() { conn.Close() })
+			return conn
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dialing %s: %v", path, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestControlServerUnix verifies the control protocol over a unix socket
+func TestControlServerUnix(t *testing.T) {
+	handler := New(slog.DiscardHandler)
+	db := New(slog.DiscardHandler)
+	registry := NewRegistry()
+	registry.Register("db", db)
+
+	server := &ControlServer{Handler: handler, Registry: registry}
+	path := filepath.Join(t.TempDir(), "ctl.sock")
+	done := make(chan error, 1)
+	go func() { done <- server.ListenAndServeUnix(path) }()
+
+	send := controlClient(t, dialUnix(t, path))
+
+	tests := []struct {
+		cmd, want string
+	}{
+		{"get", "ok unset"},
+		{"set debug", "ok"},
+		{"get", "ok DEBUG"},
+		{"set db=warn", "ok"},
+		{"get db", "ok WARN"},
+		{"origin db", "ok WARN override at layer base from control"},
+		{"list", "ok db=WARN"},
+		{"clear db", "ok"},
+		{"list", "ok db=unset"},
+		{"set loud", `error slog: level string "loud": unknown name`},
+		{"get cache", `error unknown scope "cache"`},
+		{"frobnicate", `error unknown command "frobnicate"`},
+	}
+	for _, tt := range tests {
+		if got := send(tt.cmd); got != tt.want {
+			t.Errorf("%q returned %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Errorf("handler level is %v, want %v", level, slog.LevelDebug)
+	}
+
+	if err := server.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if err := <-done; !errors.Is(err, ErrServerClosed) {
+		t.Fatalf("ListenAndServeUnix returned %v, want %v", err, ErrServerClosed)
+	}
+}
+
+// TestListenUnixInUse verifies that a live socket is kept and a stale one is replaced
+func TestListenUnixInUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ctl.sock")
+	live, err := listenUnix(path)
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	go func() {
+		for {
+			conn, err := live.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	if err := (&ControlServer{}).ListenAndServeUnix(path); !errors.Is(err, syscall.EADDRINUSE) {
+		t.Fatalf("ListenAndServeUnix on a live socket returned %v, want EADDRINUSE", err)
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("live socket was removed: %v", err)
+	}
+	conn.Close()
+
+	live.(*net.UnixListener).SetUnlinkOnClose(false)
+	live.Close()
+	stale, err := listenUnix(path)
+	if err != nil {
+		t.Fatalf("listening over a stale socket: %v", err)
+	}
+	stale.Close()
+}
+
+// TestControlServerTCP verifies token authentication and network allowlists over TCP
+func TestControlServerTCP(t *testing.T) {
+	handler := New(slog.DiscardHandler)
+	server := &ControlServer{
+		Handler:         handler,
+		Token:           "s3cret",
+		AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")},
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	go server.Serve(l)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	defer conn.Close()
+	send := controlClient(t, conn)
+	if got := send("auth wrong"); got != "error unauthorized" {
+		t.Fatalf("authenticating with a wrong token returned %q", got)
+	}
+
+	conn, err = net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	defer conn.Close()
+	send = controlClient(t, conn)
+	if _, err := fmt.Fprintln(conn, "auth s3cret"); err != nil {
+		t.Fatalf("authenticating: %v", err)
+	}
+	if got := send("set warn"); got != "ok" {
+		t.Fatalf("set returned %q", got)
+	}
+	if level, _ := handler.Level(); level != slog.LevelWarn {
+		t.Errorf("handler level is %v, want %v", level, slog.LevelWarn)
+	}
+}
+
+// TestControlServerTimeouts verifies that idle connections are closed, and that Close closes established connections
+func TestControlServerTimeouts(t *testing.T) {
+	// serve starts server and returns a function dialing it, with the
+	// authentication line sent unless auth is false.
+	serve := func(server *ControlServer) func(auth bool) net.Conn {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listening: %v", err)
+		}
+		go server.Serve(l)
+		t.Cleanup(func() { server.Close() })
+		return func(auth bool) net.Conn {
+			conn, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				t.Fatalf("dialing: %v", err)
+			}
+			t.Cleanup(func() { conn.Close() })
+			if auth {
+				fmt.Fprintln(conn, "auth s3cret")
+			}
+			return conn
+		}
+	}
+	// expectClosed waits for the server to close conn.
+	expectClosed := func(conn net.Conn, what string) {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		if _, err := io.ReadAll(conn); err != nil {
+			t.Errorf("%s was not closed: %v", what, err)
+		}
+	}
+
+	dial := serve(&ControlServer{
+		Handler:     New(slog.DiscardHandler),
+		Token:       "s3cret",
+		AuthTimeout: 20 * time.Millisecond,
+		IdleTimeout: 50 * time.Millisecond,
+	})
+	expectClosed(dial(false), "connection without auth")
+	idle := dial(true)
+	if got := controlClient(t, idle)("get"); got != "ok unset" {
+		t.Fatalf("get returned %q", got)
+	}
+	expectClosed(idle, "idle connection")
+
+	server := &ControlServer{Handler: New(slog.DiscardHandler), Token: "s3cret"}
+	open := serve(server)(true)
+	if got := controlClient(t, open)("get"); got != "ok unset" {
+		t.Fatalf("get returned %q", got)
+	}
+	server.Close()
+	expectClosed(open, "connection after Close")
+}
+
+// TestControlServerAllowedNetworks verifies that clients outside the allowlist are rejected
+func TestControlServerAllowedNetworks(t *testing.T) {
+	server := &ControlServer{
+		AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	}
+	if server.allowed(&net.TCPAddr{IP: net.ParseIP("127.0.0.1")}) {
+		t.Error("127.0.0.1 should not be allowed")
+	}
+	if !server.allowed(&net.TCPAddr{IP: net.ParseIP("10.1.2.3")}) {
+		t.Error("10.1.2.3 should be allowed")
+	}
+	if !server.allowed(&net.UnixAddr{Name: "ctl.sock", Net: "unix"}) {
+		t.Error("unix socket clients should be allowed")
+	}
+}
+
+// TestControlServerTemporarySet verifies that "set ... for" restores the previous overrides
+func TestControlServerTemporarySet(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	db := New(slog.DiscardHandler)
+	registry := NewRegistry()
+	registry.Register("db", db)
+	server := &ControlServer{Handler: handler, Registry: registry}
+
+	if _, err := server.execute("set debug for 20ms"); err != nil {
+		t.Fatalf("set returned error: %v", err)
+	}
+	if _, err := server.execute("set db=debug for 20ms"); err != nil {
+		t.Fatalf("set returned error: %v", err)
+	}
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Fatalf("handler level is %v, want %v", level, slog.LevelDebug)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for db.HasOverride() || formatOverride(handler) != "WARN" {
+		if time.Now().After(deadline) {
+			t.Fatalf("overrides were not restored: handler=%s db=%s", formatOverride(handler), formatOverride(db))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, err := server.execute("set debug for never"); err == nil {
+		t.Fatal("set should reject an invalid duration")
+	}
+}
+
+// TestControlServerTemporarySetChangedMeanwhile verifies that a temporary set does not restore over later changes
+func TestControlServerTemporarySetChangedMeanwhile(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	server := &ControlServer{Handler: handler}
+
+	if _, err := server.execute("set debug for 20ms"); err != nil {
+		t.Fatalf("set returned error: %v", err)
+	}
+	if _, err := server.execute("set info"); err != nil {
+		t.Fatalf("set returned error: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if level, _ := handler.Level(); level != slog.LevelInfo {
+		t.Errorf("handler level is %v after expiry, want %v", level, slog.LevelInfo)
+	}
+
+	if _, err := server.execute("set debug for 20ms"); err != nil {
+		t.Fatalf("set returned error: %v", err)
+	}
+	server.Close()
+	time.Sleep(100 * time.Millisecond)
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Errorf("handler level is %v after Close, want %v", level, slog.LevelDebug)
+	}
+}
+
+// TestControlServerTemporarySetConcurrentChange verifies that a change landing right after a temporary set survives its restore
+func TestControlServerTemporarySetConcurrentChange(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	server := &ControlServer{Handler: handler}
+
+	// The watcher runs once the temporary override is published, before
+	// the set command returns, like an operator racing it.
+	var fired atomic.Bool
+	unwatch := handler.state.watch(func(slog.Leveler) {
+		if fired.CompareAndSwap(false, true) {
+			handler.SetLevel(slog.LevelInfo)
+		}
+	})
+	defer unwatch()
+
+	if _, err := server.execute("set debug for 20ms"); err != nil {
+		t.Fatalf("set returned error: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if level, _ := handler.Level(); level != slog.LevelInfo {
+		t.Errorf("handler level is %v after expiry, want %v", level, slog.LevelInfo)
+	}
+}
+
+// TestControlServerDump verifies the multi-line response of the dump command
+func TestControlServerDump(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelInfo, WithSuppressedBuffer(8, 0))
+	server := &ControlServer{Handler: handler}
+
+	var out strings.Builder
+	if err := server.ServeStream(strings.NewReader("dump\n"), &out); err != nil {
+		t.Fatalf("ServeStream failed: %v", err)
+	}
+	if got := out.String(); got != "ok 0\n" {
+		t.Fatalf("empty dump returned %q, want %q", got, "ok 0\n")
+	}
+
+	logger := slog.New(handler)
+	logger.Debug("first")
+	logger.Debug("second")
+
+	out.Reset()
+	if err := server.ServeStream(strings.NewReader("dump\n"), &out); err != nil {
+		t.Fatalf("ServeStream failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 || lines[0] != "ok 2" || !strings.Contains(lines[1], "msg=first") || !strings.Contains(lines[2], "msg=second") {
+		t.Fatalf("dump returned %q", out.String())
+	}
+}
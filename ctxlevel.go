@@ -0,0 +1,46 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+)
+
+// contextLevelKey is the context key of the level set with
+// [ContextWithLevel].
+type contextLevelKey struct{}
+
+// ContextWithLevel returns a copy of ctx carrying level, which handlers
+// created with [WithContextLevels] honor for records logged with the
+// context, e.g. to enable Debug for a single request.
+func ContextWithLevel(ctx context.Context, level slog.Level) context.Context {
+	return context.WithValue(ctx, contextLevelKey{}, level)
+}
+
+// LevelFromContext returns the level carried by ctx, if any.
+func LevelFromContext(ctx context.Context) (slog.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(contextLevelKey{}).(slog.Level)
+	return level, ok
+}
+
+// WithContextLevels makes the handler honor levels carried by the context
+// of records, set with [ContextWithLevel]. A context level can only make
+// the handler more verbose: records at or above it are enabled even if the
+// threshold rejects them, but records the threshold accepts are never
+// dropped because of it. Suppression by [WithThroughputBudget],
+// [WithErrorBackoff], [WithAsync], [WithDiskGuard] and [WithDeadlineGuard]
+// still applies.
+func WithContextLevels() Option {
+	return func(o *options) {
+		o.contextLevels = true
+	}
+}
+
+// contextEnabled reports whether the level carried by ctx enables records
+// at level.
+func contextEnabled(ctx context.Context, level slog.Level) bool {
+	threshold, ok := LevelFromContext(ctx)
+	return ok && level >= threshold
+}
@@ -0,0 +1,29 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestContextLevels verifies that context levels only make the handler more verbose
+func TestContextLevels(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	logger := slog.New(NewWithLevel(assertHandler, slog.LevelInfo, WithContextLevels()))
+	debugCtx := ContextWithLevel(context.Background(), slog.LevelDebug)
+	errorCtx := ContextWithLevel(context.Background(), slog.LevelError)
+
+	logger.DebugContext(debugCtx, "debug with context")
+	logger.Debug("debug without context")
+	logger.InfoContext(errorCtx, "info with error context")
+
+	// Without the option, context levels are ignored
+	slog.New(NewWithLevel(assertHandler, slog.LevelInfo)).DebugContext(debugCtx, "ignored")
+
+	assertHandler.AssertMessage("debug with context")
+	assertHandler.AssertMessage("info with error context")
+}
@@ -0,0 +1,169 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// InstallDefault wraps the handler of [slog.Default] in an [OverrideHandler]
+// with the given level, installs the result with [slog.SetDefault] and
+// returns the handler for later SetLevel calls. A nil level installs the
+// handler without override.
+//
+// If the default logger still uses the built-in handler of the slog
+// package, which writes through the log package, it is replaced by an
+// equivalent handler writing to the current output of the log package.
+// Wrapping the built-in handler directly would deadlock, since slog.SetDefault
+// redirects the log package to the new default logger.
+func InstallDefault(level slog.Leveler, opts ...Option) *OverrideHandler {
+	base := slog.Default().Handler()
+	if isBuiltinDefault(base) {
+		base = newStdlogHandler(base)
+	}
+	h := New(base, opts...)
+	if level != nil {
+		h.SetLevel(level)
+	}
+	slog.SetDefault(slog.New(h))
+	return h
+}
+
+// NewLazyDefault returns an [OverrideHandler] with the given level wrapping
+// a [LazyDefaultHandler], for packages that grab their handler early, before
+// the application has installed its default logger. A nil level creates the
+// handler without override.
+func NewLazyDefault(level slog.Leveler, opts ...Option) *OverrideHandler {
+	h := New(LazyDefaultHandler{}, opts...)
+	if level != nil {
+		h.SetLevel(level)
+	}
+	return h
+}
+
+// LazyDefaultHandler is a [slog.Handler] forwarding to the handler of
+// [slog.Default] as of each call, so that a logger created from it follows
+// later calls to slog.SetDefault. Attributes and groups added with WithAttrs
+// and WithGroup are applied to the resolved handler on every call.
+//
+// The zero value is ready to use. A LazyDefaultHandler must not be part of
+// the default logger itself, since it would then forward to itself.
+type LazyDefaultHandler struct {
+	derive []func(slog.Handler) slog.Handler
+}
+
+// resolve returns the handler of the default logger with the attributes and
+// groups of h applied.
+func (h LazyDefaultHandler) resolve() slog.Handler {
+	handler := slog.Default().Handler()
+	for _, derive := range h.derive {
+		handler = derive(handler)
+	}
+	return handler
+}
+
+// Enabled reports whether the handler of the default logger is enabled for
+// level.
+func (h LazyDefaultHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return slog.Default().Handler().Enabled(ctx, level)
+}
+
+// Handle forwards record to the handler of the default logger.
+func (h LazyDefaultHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.resolve().Handle(ctx, record)
+}
+
+// WithAttrs returns a handler adding attrs to the handler of the default
+// logger.
+func (h LazyDefaultHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+// WithGroup returns a handler opening the group name on the handler of the
+// default logger.
+func (h LazyDefaultHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h LazyDefaultHandler) with(derive func(slog.Handler) slog.Handler) LazyDefaultHandler {
+	return LazyDefaultHandler{derive: append(slices.Clip(h.derive), derive)}
+}
+
+// isBuiltinDefault reports whether h is the built-in handler used by the
+// slog package before slog.SetDefault is called.
+func isBuiltinDefault(h slog.Handler) bool {
+	return fmt.Sprintf("%T", h) == "*slog.defaultHandler"
+}
+
+// stdlogHandler formats records like the built-in default handler of the
+// slog package, "LEVEL message key=value", and writes them to a
+// [log.Logger] of its own, which keeps writing to the destination the log
+// package had when it was created.
+type stdlogHandler struct {
+	level  slog.Level
+	shared *stdlogShared
+	attrs  slog.Handler
+}
+
+type stdlogShared struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	logger *log.Logger
+}
+
+func newStdlogHandler(builtin slog.Handler) *stdlogHandler {
+	shared := &stdlogShared{
+		logger: log.New(log.Writer(), log.Prefix(), log.Flags()),
+	}
+	return &stdlogHandler{
+		level:  handlerLevel(context.Background(), builtin),
+		shared: shared,
+		attrs: slog.NewTextHandler(&shared.buf, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 {
+					switch a.Key {
+					case slog.TimeKey, slog.LevelKey, slog.MessageKey:
+						return slog.Attr{}
+					}
+				}
+				return a
+			},
+		}),
+	}
+}
+
+func (h *stdlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *stdlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.shared.mu.Lock()
+	defer h.shared.mu.Unlock()
+
+	h.shared.buf.Reset()
+	if err := h.attrs.Handle(ctx, record); err != nil {
+		return err
+	}
+	line := record.Level.String() + " " + record.Message
+	if attrs := strings.TrimSuffix(h.shared.buf.String(), "\n"); attrs != "" {
+		line += " " + attrs
+	}
+	return h.shared.logger.Output(0, line)
+}
+
+func (h *stdlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &stdlogHandler{level: h.level, shared: h.shared, attrs: h.attrs.WithAttrs(attrs)}
+}
+
+func (h *stdlogHandler) WithGroup(name string) slog.Handler {
+	return &stdlogHandler{level: h.level, shared: h.shared, attrs: h.attrs.WithGroup(name)}
+}
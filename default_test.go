@@ -0,0 +1,67 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestInstallDefault verifies that the default logger becomes tunable without deadlocking the log package
+func TestInstallDefault(t *testing.T) {
+	previous := slog.Default()
+	var buf bytes.Buffer
+	flags, output := log.Flags(), log.Writer()
+	log.SetFlags(0)
+	log.SetOutput(&buf)
+	defer func() {
+		slog.SetDefault(previous)
+		log.SetFlags(flags)
+		log.SetOutput(output)
+	}()
+
+	// Start from the built-in default handler
+	slog.SetDefault(slog.New(slog.Default().Handler()))
+	handler := InstallDefault(slog.LevelWarn)
+
+	slog.Info("info message")
+	slog.With("component", "db").Warn("warn message", "attempt", 2)
+
+	// The log package goes through the override at Info level
+	log.Print("dropped")
+	handler.SetLevel(slog.LevelInfo)
+	log.Print("from log")
+
+	want := "WARN warn message component=db attempt=2\n" +
+		"INFO from log\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("output is %q, want %q", got, want)
+	}
+}
+
+// TestLazyDefault verifies that a handler created early follows later changes of the default logger
+func TestLazyDefault(t *testing.T) {
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+
+	handler := NewLazyDefault(slog.LevelWarn)
+	logger := slog.New(handler).With("component", "db").WithGroup("req")
+
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+	slog.SetDefault(slog.New(assertHandler))
+
+	logger.Info("info message")
+	logger.Warn("warn message", "id", 7)
+	handler.SetLevel(slog.LevelDebug)
+	logger.Debug("debug message")
+
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "warn message",
+		Level:   slog.LevelWarn,
+		Attrs:   map[string]any{"component": "db", "req.id": int64(7)},
+	})
+	assertHandler.AssertMessage("debug message")
+}
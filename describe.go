@@ -0,0 +1,135 @@
+package slogleveloverride
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Description is a snapshot of the configuration and state of an
+// [OverrideHandler], returned by [OverrideHandler.Describe] for startup logs
+// and debug endpoints.
+type Description struct {
+	// Chain is the type of the wrapped handler followed by the types of the
+	// handlers it wraps, found as by [FindOverrideHandler].
+	Chain []string `json:"chain"`
+	// Level is the effective level and Origin what determined it, as
+	// reported by [OverrideHandler.EffectiveLevel].
+	Level  slog.Level `json:"level"`
+	Origin string     `json:"origin"`
+	// Attrs and Groups are the numbers of attributes and groups added with
+	// WithAttrs and WithGroup.
+	Attrs  int `json:"attrs"`
+	Groups int `json:"groups"`
+	// Features are the names of the options and rules in use, such as
+	// "suppressed-digest" for [WithSuppressedDigest].
+	Features []string `json:"features,omitempty"`
+}
+
+// String formats d on a single line, e.g.
+// "level=INFO origin="override at layer base from code" chain=*slog.TextHandler attrs=1 groups=0".
+func (d Description) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "level=%v origin=%q chain=%s attrs=%d groups=%d", d.Level, d.Origin, strings.Join(d.Chain, ">"), d.Attrs, d.Groups)
+	if len(d.Features) > 0 {
+		b.WriteString(" features=" + strings.Join(d.Features, ","))
+	}
+	return b.String()
+}
+
+// LogValue returns d as a group, so that it can be logged as an attribute.
+func (d Description) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Any("level", d.Level),
+		slog.String("origin", d.Origin),
+		slog.String("chain", strings.Join(d.Chain, ">")),
+		slog.Int("attrs", d.Attrs),
+		slog.Int("groups", d.Groups),
+	}
+	if len(d.Features) > 0 {
+		attrs = append(attrs, slog.String("features", strings.Join(d.Features, ",")))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Describe returns a snapshot of the configuration and state of h: the
+// chain of wrapped handler types, the effective level and its origin, the
+// attributes and groups added, and the features in use.
+func (h *OverrideHandler) Describe() Description {
+	level, origin := h.EffectiveLevel()
+	return Description{
+		Chain:    handlerChain(h.wrapped()),
+		Level:    level,
+		Origin:   origin.String(),
+		Attrs:    h.attrCount,
+		Groups:   h.groups,
+		Features: h.opts.features(),
+	}
+}
+
+// String describes h on a single line, as formatted by
+// [Description.String].
+func (h *OverrideHandler) String() string {
+	return h.Describe().String()
+}
+
+// handlerChain returns the types of h and of the handlers it wraps.
+func handlerChain(h slog.Handler) []string {
+	var chain []string
+	for range maxUnwrapDepth {
+		if h == nil {
+			break
+		}
+		chain = append(chain, fmt.Sprintf("%T", h))
+		switch w := h.(type) {
+		case interface{ Unwrap() slog.Handler }:
+			h = w.Unwrap()
+		case interface{ Handler() slog.Handler }:
+			h = w.Handler()
+		default:
+			return chain
+		}
+	}
+	return chain
+}
+
+// features returns the names of the options and rules in use.
+func (o *options) features() []string {
+	var features []string
+	add := func(on bool, name string) {
+		if on {
+			features = append(features, name)
+		}
+	}
+	add(o.isolatedChildren, "isolated-children")
+	add(o.handleFiltering, "handle-filtering")
+	add(o.panicRecovery, "panic-recovery")
+	add(o.forceAllWarning, "force-all-warning")
+	add(o.contextLevels, "context-levels")
+	add(o.overriddenKey != "", "override-annotation")
+	add(o.thresholdKey != "", "threshold-attr")
+	add(o.stackKey != "", "diagnostic-stacks")
+	add(o.deadlineMargin > 0, "deadline-guard")
+	add(o.throughput != nil, "throughput-budget")
+	add(o.rateLimit != nil, "attr-rate-limit")
+	add(o.repeatBackoff != nil, "repeat-backoff")
+	add(o.digest != nil, "suppressed-digest")
+	add(o.mismatch != nil, "mismatch-check")
+	add(o.handleErrors != nil, "handle-errors")
+	add(o.errorBackoff != nil, "error-backoff")
+	add(o.fallback != nil, "fallback")
+	add(o.async != nil, "async")
+	add(o.diskGuard != nil, "disk-guard")
+	add(o.traceBuffer != nil && o.traceBuffer.traceID != nil, "trace-buffer")
+	add(o.traceBuffer != nil && o.traceBuffer.traceID == nil, "keyed-buffer")
+	add(o.suppressed != nil, "suppressed-buffer")
+	add(o.allowlist != nil, "debug-allowlist")
+	add(o.sideSink != nil, "side-sink")
+	add(o.memory != nil, "memory-budget")
+	add(o.sourceRules.Load() != nil, "source-rules")
+	add(o.remapRules.Load() != nil, "remap-rules")
+	add(o.rules.Load() != nil, "rules")
+	add(o.diagnostics.Load(), "diagnostics")
+	add(o.replaced.Load() != nil, "replaced-handler")
+	return features
+}
@@ -0,0 +1,42 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDescribe verifies that the description reports the chain, level, depth and features
+func TestDescribe(t *testing.T) {
+	var out bytes.Buffer
+	base := NewWithLevel(slog.NewTextHandler(&out, nil), slog.LevelWarn, WithSuppressedDigest(time.Minute, 3), WithRepeatBackoff(time.Minute, 8))
+	handler := base.WithAttrs([]slog.Attr{slog.Int("a", 1), slog.Int("b", 2)}).WithGroup("g").(*OverrideHandler)
+	handler.SetRules(Rule{Level: slog.LevelDebug})
+
+	d := handler.Describe()
+	if !slices.Equal(d.Chain, []string{"*slog.TextHandler"}) {
+		t.Errorf("Chain = %v", d.Chain)
+	}
+	if d.Level != slog.LevelWarn || d.Origin != "override at layer base from code" {
+		t.Errorf("Level = %v, Origin = %q", d.Level, d.Origin)
+	}
+	if d.Attrs != 2 || d.Groups != 1 {
+		t.Errorf("Attrs = %d, Groups = %d, want 2, 1", d.Attrs, d.Groups)
+	}
+	if want := []string{"repeat-backoff", "suppressed-digest", "rules"}; !slices.Equal(d.Features, want) {
+		t.Errorf("Features = %v, want %v", d.Features, want)
+	}
+
+	want := `level=WARN origin="override at layer base from code" chain=*slog.TextHandler attrs=2 groups=1 features=repeat-backoff,suppressed-digest,rules`
+	if got := handler.String(); got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+
+	slog.New(base).Warn("starting", "handler", base.Describe())
+	if !strings.Contains(out.String(), "handler.level=WARN") || !strings.Contains(out.String(), "handler.attrs=0") {
+		t.Errorf("logged description %q", out.String())
+	}
+}
@@ -0,0 +1,143 @@
+package slogleveloverride
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DigestMessage is the message of the records emitted by
+// [WithSuppressedDigest].
+const DigestMessage = "suppressed records digest"
+
+// suppressedDigest counts suppressed records per level and message.
+type suppressedDigest struct {
+	interval time.Duration
+	top      int
+	budget   *MemoryBudget
+
+	mu     sync.Mutex
+	since  time.Time
+	counts map[digestKey]int
+}
+
+type digestKey struct {
+	level   slog.Level
+	message string
+}
+
+// WithSuppressedDigest counts the records suppressed by the level threshold
+// per level and message, and emits a summary record of the top most
+// frequent ones every interval, giving visibility into what verbose logging
+// is being missed.
+//
+// The digest is emitted at Info level, bypassing the threshold, by the
+// first handler call after interval has elapsed, with attributes
+// "suppressed_total" and a "top" group with one group per entry.
+//
+// To see suppressed records at all, Enabled reports true for every level and
+// the threshold is applied in Handle, so records below the threshold are
+// still built by the logger.
+func WithSuppressedDigest(interval time.Duration, top int) Option {
+	return func(o *options) {
+		o.digest = &suppressedDigest{
+			interval: interval,
+			top:      top,
+			since:    time.Now(),
+			counts:   make(map[digestKey]int),
+		}
+	}
+}
+
+// count accounts for a suppressed record.
+func (d *suppressedDigest) count(record slog.Record) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := digestKey{level: record.Level, message: record.Message}
+	if _, ok := d.counts[key]; !ok && !d.budget.reserve(digestKeySize(key)) {
+		d.budget.reject()
+		return
+	}
+	d.counts[key]++
+}
+
+// digestKeySize estimates the memory held by a count for key.
+func digestKeySize(key digestKey) int64 {
+	return int64(64 + len(key.message))
+}
+
+// take returns the digest record if interval has elapsed and records were
+// suppressed, and starts a new interval.
+func (d *suppressedDigest) take(now time.Time) (slog.Record, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if now.Sub(d.since) < d.interval {
+		return slog.Record{}, false
+	}
+	counts := d.counts
+	d.since = now
+	if len(counts) == 0 {
+		return slog.Record{}, false
+	}
+	d.counts = make(map[digestKey]int)
+	for k := range counts {
+		d.budget.release(digestKeySize(k))
+	}
+
+	keys := make([]digestKey, 0, len(counts))
+	total := 0
+	for k, n := range counts {
+		keys = append(keys, k)
+		total += n
+	}
+	slices.SortFunc(keys, func(a, b digestKey) int {
+		return cmp.Or(
+			cmp.Compare(counts[b], counts[a]),
+			cmp.Compare(a.level, b.level),
+			cmp.Compare(a.message, b.message),
+		)
+	})
+	keys = keys[:min(len(keys), d.top)]
+
+	top := make([]any, len(keys))
+	for i, k := range keys {
+		top[i] = slog.Group(strconv.Itoa(i+1),
+			slog.String("level", k.level.String()),
+			slog.String("msg", k.message),
+			slog.Int("count", counts[k]),
+		)
+	}
+	r := slog.NewRecord(now, slog.LevelInfo, DigestMessage, 0)
+	r.AddAttrs(slog.Int("suppressed_total", total), slog.Group("top", top...))
+	return r, true
+}
+
+// handleSuppressed accounts for a record suppressed in Handle.
+func (h *OverrideHandler) handleSuppressed(ctx context.Context, record slog.Record) {
+	if h.opts.digest != nil {
+		h.opts.digest.count(record)
+	}
+	if h.opts.traceBuffer != nil {
+		h.opts.traceBuffer.add(ctx, h, record)
+	}
+	if h.opts.suppressed != nil {
+		h.opts.suppressed.add(record, h.attrs)
+	}
+	h.handleSide(ctx, record)
+}
+
+// emitDigest sends the digest record to the handler passed to New when due.
+// The digest covers the whole family of h, so it is sent without the
+// attributes and groups of h.
+func (h *OverrideHandler) emitDigest(ctx context.Context) {
+	if h.opts.digest == nil {
+		return
+	}
+	if r, ok := h.opts.digest.take(time.Now()); ok {
+		_ = h.root().Handle(ctx, r)
+	}
+}
@@ -0,0 +1,81 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestWithSuppressedDigest verifies that suppressed records are summarized
+func TestWithSuppressedDigest(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelWarn, WithSuppressedDigest(time.Hour, 2))
+	logger := slog.New(handler)
+
+	for range 3 {
+		logger.Debug("cache miss")
+	}
+	logger.Info("request served")
+	logger.Info("request served")
+	logger.Debug("rare")
+
+	// The next call after the interval emits the digest
+	handler.opts.digest.since = time.Now().Add(-time.Hour)
+	logger.Warn("warn message")
+
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message: DigestMessage,
+		Level:   slog.LevelInfo,
+		Attrs: map[string]any{
+			"suppressed_total": int64(6),
+			"top.1.level":      "DEBUG",
+			"top.1.msg":        "cache miss",
+			"top.1.count":      int64(3),
+			"top.2.msg":        "request served",
+			"top.2.count":      int64(2),
+		},
+	})
+	assertHandler.AssertMessage("warn message")
+}
+
+// TestSuppressedDigestDerived verifies that the digest is emitted without the attributes and groups of the handler emitting it
+func TestSuppressedDigestDerived(t *testing.T) {
+	var out bytes.Buffer
+	handler := NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelWarn}, WithSuppressedDigest(time.Hour, 1))
+	logger := slog.New(handler).With("user", "alice").WithGroup("req")
+
+	logger.Debug("cache miss")
+	handler.opts.digest.since = time.Now().Add(-time.Hour)
+	logger.Warn("warn message")
+
+	digest, _, _ := strings.Cut(out.String(), "\n")
+	if !strings.Contains(digest, "msg=\""+DigestMessage+"\"") || !strings.Contains(digest, " suppressed_total=1 ") {
+		t.Fatalf("first line %q is not the digest", digest)
+	}
+	if strings.Contains(digest, "alice") || strings.Contains(digest, "req.") {
+		t.Errorf("digest %q carries the attributes or groups of the derived handler", digest)
+	}
+}
+
+// TestSuppressedDigestInterval verifies that the digest is only emitted once per interval
+func TestSuppressedDigestInterval(t *testing.T) {
+	start := time.Now()
+	digest := &suppressedDigest{interval: time.Minute, top: 10, since: start, counts: make(map[digestKey]int)}
+	digest.count(slog.NewRecord(start, slog.LevelDebug, "debug", 0))
+
+	if _, ok := digest.take(start.Add(time.Second)); ok {
+		t.Fatal("digest should not be emitted before the interval elapsed")
+	}
+	if _, ok := digest.take(start.Add(time.Minute)); !ok {
+		t.Fatal("digest should be emitted once the interval elapsed")
+	}
+	if _, ok := digest.take(start.Add(2 * time.Minute)); ok {
+		t.Fatal("empty digest should not be emitted")
+	}
+}
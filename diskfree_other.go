@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd || dragonfly)
+
+package slogleveloverride
+
+import "errors"
+
+// diskFree reports that free space cannot be determined on this platform.
+func diskFree(string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
@@ -0,0 +1,98 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// diskGuard raises the level while the free space of a volume is low.
+type diskGuard struct {
+	path     string
+	minFree  uint64
+	level    slog.Level
+	interval time.Duration
+
+	// free returns the bytes available to unprivileged users on the volume
+	// of path.
+	free func(path string) (uint64, error)
+
+	nextCheck atomic.Int64
+	low       atomic.Bool
+}
+
+// WithDiskGuard monitors the free space of the volume holding path, the log
+// volume, and suppresses records below level while less than minFree bytes
+// are available, keeping logging from filling the disk.
+//
+// Free space is checked at most once per interval, during handler calls,
+// including calls to Enabled for suppressed levels so that the guard
+// releases even if nothing is logged at or above level.
+// When the guard engages or releases, a Warn record explaining the change is
+// sent to the underlying handler. The guard is shared by all handlers
+// derived from the same handler. On platforms where free space cannot be
+// determined, the guard never engages.
+func WithDiskGuard(path string, minFree uint64, level slog.Level, interval time.Duration) Option {
+	return func(o *options) {
+		o.diskGuard = &diskGuard{
+			path:     path,
+			minFree:  minFree,
+			level:    level,
+			interval: interval,
+			free:     diskFree,
+		}
+	}
+}
+
+// suppresses reports whether a record at level is currently suppressed
+// because free space is low.
+func (g *diskGuard) suppresses(level slog.Level) bool {
+	return level < g.level && g.low.Load()
+}
+
+// diskSuppresses reports whether a record at level is suppressed by the
+// guard. While the guard suppresses it, free space is checked if due, since
+// such records never reach Handle.
+func (h *OverrideHandler) diskSuppresses(ctx context.Context, level slog.Level) bool {
+	g := h.opts.diskGuard
+	if !g.suppresses(level) {
+		return false
+	}
+	h.checkDisk(ctx)
+	return g.suppresses(level)
+}
+
+// checkDisk checks the free space if due and notifies the underlying handler
+// when the guard engages or releases.
+func (h *OverrideHandler) checkDisk(ctx context.Context) {
+	g := h.opts.diskGuard
+	if g == nil {
+		return
+	}
+	now := time.Now().UnixNano()
+	next := g.nextCheck.Load()
+	if now < next || !g.nextCheck.CompareAndSwap(next, now+int64(g.interval)) {
+		return
+	}
+	free, err := g.free(g.path)
+	if err != nil {
+		return
+	}
+	low := free < g.minFree
+	if g.low.Swap(low) == low {
+		return
+	}
+	msg := "free disk space low, suppressing verbose records"
+	if !low {
+		msg = "free disk space recovered, no longer suppressing verbose records"
+	}
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, msg, 0)
+	r.AddAttrs(
+		slog.String("path", g.path),
+		slog.Uint64("free_bytes", free),
+		slog.Uint64("min_free_bytes", g.minFree),
+		slog.String("min_level", g.level.String()),
+	)
+	_ = h.wrapped().Handle(ctx, r)
+}
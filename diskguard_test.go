@@ -0,0 +1,86 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestDiskGuard verifies that low free space raises the level and a Warn explains it
+func TestDiskGuard(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelDebug, WithDiskGuard("/var/log", 1<<30, slog.LevelWarn, time.Hour))
+	free := uint64(1 << 20)
+	handler.opts.diskGuard.free = func(path string) (uint64, error) {
+		if path != "/var/log" {
+			t.Errorf("free space checked for %q, want %q", path, "/var/log")
+		}
+		return free, nil
+	}
+	logger := slog.New(handler)
+
+	logger.Warn("engages guard")
+	logger.Info("dropped while low")
+
+	free = 2 << 30
+	logger.Warn("not checked before interval")
+	handler.opts.diskGuard.nextCheck.Store(0)
+	logger.Warn("releases guard")
+	logger.Info("forwarded after release")
+
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "free disk space low, suppressing verbose records",
+		Level:   slog.LevelWarn,
+		Attrs: map[string]any{
+			"path":           "/var/log",
+			"free_bytes":     uint64(1 << 20),
+			"min_free_bytes": uint64(1 << 30),
+			"min_level":      "WARN",
+		},
+		AllAttrsMatch: true,
+	})
+	assertHandler.AssertMessage("engages guard")
+	assertHandler.AssertMessage("not checked before interval")
+	assertHandler.AssertMessage("free disk space recovered, no longer suppressing verbose records")
+	assertHandler.AssertMessage("releases guard")
+	assertHandler.AssertMessage("forwarded after release")
+}
+
+// TestDiskGuardReleasesWithoutHandle verifies that the guard releases when only suppressed levels are logged
+func TestDiskGuardReleasesWithoutHandle(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelDebug, WithDiskGuard("/var/log", 1<<30, slog.LevelWarn, time.Hour))
+	free := uint64(1 << 20)
+	handler.opts.diskGuard.free = func(string) (uint64, error) { return free, nil }
+	logger := slog.New(handler)
+
+	logger.Info("engages guard")
+	logger.Info("dropped while low")
+
+	free = 2 << 30
+	logger.Info("not checked before interval")
+	handler.opts.diskGuard.nextCheck.Store(0)
+	logger.Info("forwarded after release")
+
+	assertHandler.AssertMessage("free disk space low, suppressing verbose records")
+	assertHandler.AssertMessage("engages guard")
+	assertHandler.AssertMessage("free disk space recovered, no longer suppressing verbose records")
+	assertHandler.AssertMessage("forwarded after release")
+}
+
+// TestDiskFree verifies that free space can be determined for the working directory
+func TestDiskFree(t *testing.T) {
+	free, err := diskFree(".")
+	if err != nil {
+		t.Skipf("free space unsupported: %v", err)
+	}
+	if free == 0 {
+		t.Error("diskFree returned 0 bytes")
+	}
+}
@@ -0,0 +1,65 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// DwellLeveler is a [slog.Leveler] following Source with a minimum dwell
+// time per level, so that a flapping source, such as a file watcher or
+// remote configuration changing many times a second, cannot make the
+// handler thrash between levels.
+//
+// Once the level changes, it is kept for at least MinDwell; changes of
+// Source in the meantime are ignored, except for the latest one, which is
+// picked up by the first call to Level after MinDwell has elapsed.
+//
+// The fields must not be changed after the first call to Level.
+type DwellLeveler struct {
+	Source   slog.Leveler
+	MinDwell time.Duration
+
+	// now returns the current time.
+	now func() time.Time
+
+	current atomic.Pointer[dwellState]
+}
+
+// dwellState is the level in effect and when it took effect.
+type dwellState struct {
+	level slog.Level
+	since time.Time
+}
+
+// NewDwellLeveler creates a [DwellLeveler] following source, keeping each
+// level for at least minDwell.
+func NewDwellLeveler(source slog.Leveler, minDwell time.Duration) *DwellLeveler {
+	return &DwellLeveler{Source: source, MinDwell: minDwell}
+}
+
+// Level returns the level of Source, or the previous level if it took effect
+// less than MinDwell ago.
+func (d *DwellLeveler) Level() slog.Level {
+	level := d.Source.Level()
+	current := d.current.Load()
+	if current != nil && current.level == level {
+		return level
+	}
+	now := d.clock()
+	if current != nil && now.Sub(current.since) < d.MinDwell {
+		return current.level
+	}
+	if !d.current.CompareAndSwap(current, &dwellState{level: level, since: now}) {
+		return d.current.Load().level
+	}
+	return level
+}
+
+// clock returns the current time.
+func (d *DwellLeveler) clock() time.Time {
+	if d.now != nil {
+		return d.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,37 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestDwellLeveler verifies that level changes within the dwell time are held back
+func TestDwellLeveler(t *testing.T) {
+	var source slog.LevelVar
+	now := time.Now()
+	leveler := NewDwellLeveler(&source, time.Minute)
+	leveler.now = func() time.Time { return now }
+
+	steps := []struct {
+		advance time.Duration
+		source  slog.Level
+		want    slog.Level
+	}{
+		{0, slog.LevelInfo, slog.LevelInfo},
+		{time.Second, slog.LevelDebug, slog.LevelInfo},
+		{time.Second, slog.LevelWarn, slog.LevelInfo},
+		{time.Second, slog.LevelError, slog.LevelInfo},
+		{time.Minute, slog.LevelError, slog.LevelError},
+		{time.Second, slog.LevelDebug, slog.LevelError},
+		{time.Second, slog.LevelError, slog.LevelError},
+		{2 * time.Minute, slog.LevelDebug, slog.LevelDebug},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		source.Set(step.source)
+		if got := leveler.Level(); got != step.want {
+			t.Fatalf("step %d: Level = %v, want %v", i, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package slogleveloverride
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// NewForEnvironment creates an [OverrideHandler] wrapping h with the preset
+// of the named environment, reducing per-service boilerplate:
+//
+//   - "production": Info level, with a throughput budget of 1000 records per
+//     second shedding records below Warn for 30 seconds.
+//   - "staging": Debug level, with a throughput budget of 5000 records per
+//     second shedding records below Info for 10 seconds, and diagnostic
+//     stacks.
+//   - "development": Debug level, with diagnostic stacks and a warning when
+//     h itself filters records the override lets through.
+//
+// The environment name is case-insensitive. opts are applied after the
+// preset, so they can replace parts of it, and the level can be changed at
+// runtime as usual. An error is returned for unknown environments.
+func NewForEnvironment(h slog.Handler, env string, opts ...Option) (*OverrideHandler, error) {
+	var (
+		level  slog.Level
+		preset []Option
+	)
+	switch strings.ToLower(env) {
+	case "production":
+		level = slog.LevelInfo
+		preset = []Option{
+			WithThroughputBudget(1000, slog.LevelWarn, 30*time.Second),
+		}
+	case "staging":
+		level = slog.LevelDebug
+		preset = []Option{
+			WithThroughputBudget(5000, slog.LevelInfo, 10*time.Second),
+			WithDiagnosticStacks(""),
+		}
+	case "development":
+		level = slog.LevelDebug
+		preset = []Option{
+			WithDiagnosticStacks(""),
+			WithMismatchCheck(0, nil),
+		}
+	default:
+		return nil, fmt.Errorf("slogleveloverride: unknown environment %q", env)
+	}
+	return NewWithLevel(h, level, append(preset, opts...)...), nil
+}
@@ -0,0 +1,43 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestNewForEnvironment verifies the level of each preset and unknown environments
+func TestNewForEnvironment(t *testing.T) {
+	tests := map[string]slog.Level{
+		"production":  slog.LevelInfo,
+		"Staging":     slog.LevelDebug,
+		"DEVELOPMENT": slog.LevelDebug,
+	}
+	for env, want := range tests {
+		handler, err := NewForEnvironment(slog.DiscardHandler, env)
+		if err != nil {
+			t.Fatalf("NewForEnvironment(%q) failed: %v", env, err)
+		}
+		if level, ok := handler.Level(); !ok || level != want {
+			t.Errorf("NewForEnvironment(%q) level is (%v, %v), want (%v, true)", env, level, ok, want)
+		}
+	}
+	if _, err := NewForEnvironment(slog.DiscardHandler, "qa"); err == nil {
+		t.Error("NewForEnvironment should fail for an unknown environment")
+	}
+}
+
+// TestNewForEnvironmentOptions verifies that options are applied after the preset
+func TestNewForEnvironmentOptions(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler, err := NewForEnvironment(assertHandler, "development", WithMismatchCheck(0, func(Mismatch) {}))
+	if err != nil {
+		t.Fatalf("NewForEnvironment failed: %v", err)
+	}
+	// The preset would warn about the Info level of assertHandler
+	slog.New(handler).Info("no mismatch warning")
+	assertHandler.AssertMessage("no mismatch warning")
+}
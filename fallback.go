@@ -0,0 +1,63 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// fallbackState tracks whether the underlying handler is considered down.
+type fallbackState struct {
+	handler       slog.Handler
+	probeInterval time.Duration
+
+	down      atomic.Bool
+	nextProbe atomic.Int64
+}
+
+// WithFallback sends records to fallback when the underlying handler fails
+// to handle them, e.g. to write to stderr while a network sink is down.
+//
+// After a failure, the underlying handler is considered down and records
+// go straight to fallback, except for one record every probeInterval, which
+// probes the underlying handler again. Once a probe succeeds, records go to
+// the underlying handler again. Failures are still reported as configured
+// with [WithHandleErrors] and [WithErrorBackoff]; Handle returns the error
+// of fallback, if any.
+func WithFallback(fallback slog.Handler, probeInterval time.Duration) Option {
+	return func(o *options) {
+		o.fallback = &fallbackState{handler: fallback, probeInterval: probeInterval}
+	}
+}
+
+// FallbackActive reports whether records currently go to the handler of
+// [WithFallback] because the underlying handler is down.
+func (h *OverrideHandler) FallbackActive() bool {
+	return h.opts.fallback != nil && h.opts.fallback.down.Load()
+}
+
+// bypasses reports whether the underlying handler is down and not due for a
+// probe, so that records go straight to the fallback.
+func (f *fallbackState) bypasses() bool {
+	if !f.down.Load() {
+		return false
+	}
+	now := time.Now().UnixNano()
+	next := f.nextProbe.Load()
+	return now < next || !f.nextProbe.CompareAndSwap(next, now+int64(f.probeInterval))
+}
+
+// fail marks the underlying handler as down until the next probe.
+func (f *fallbackState) fail() {
+	f.nextProbe.Store(time.Now().Add(f.probeInterval).UnixNano())
+	f.down.Store(true)
+}
+
+// handleFallback sends record to the fallback handler of h.
+func (h *OverrideHandler) handleFallback(ctx context.Context, record slog.Record) error {
+	if !h.fallback.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.fallback.Handle(ctx, record)
+}
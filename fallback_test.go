@@ -0,0 +1,54 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestFallback verifies that records go to the fallback while the underlying handler is down
+func TestFallback(t *testing.T) {
+	primary := slogassert.New(t, slog.LevelInfo, nil)
+	defer primary.AssertEmpty()
+	secondary := slogassert.New(t, slog.LevelInfo, nil)
+	defer secondary.AssertEmpty()
+
+	basic := &failingHandler{Handler: primary, failures: 2}
+	handler := New(basic, WithFallback(secondary, time.Hour))
+	logger := slog.New(handler).With("component", "db")
+
+	logger.Info("first failure")
+	if !handler.FallbackActive() {
+		t.Fatal("fallback should be active after a failure")
+	}
+	logger.Info("bypassing primary")
+	if basic.calls != 1 {
+		t.Fatalf("primary called %d times while down, want 1", basic.calls)
+	}
+
+	// A due probe fails and keeps the fallback active
+	handler.opts.fallback.nextProbe.Store(0)
+	logger.Info("failed probe")
+	if !handler.FallbackActive() {
+		t.Fatal("fallback should stay active after a failed probe")
+	}
+
+	handler.opts.fallback.nextProbe.Store(0)
+	logger.Info("successful probe")
+	if handler.FallbackActive() {
+		t.Fatal("fallback should be inactive after a successful probe")
+	}
+	logger.Info("back to primary")
+
+	for _, msg := range []string{"first failure", "bypassing primary", "failed probe"} {
+		secondary.AssertPrecise(slogassert.LogMessageMatch{
+			Message: msg,
+			Level:   slog.LevelInfo,
+			Attrs:   map[string]any{"component": "db"},
+		})
+	}
+	primary.AssertMessage("successful probe")
+	primary.AssertMessage("back to primary")
+}
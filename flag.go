@@ -0,0 +1,106 @@
+package slogleveloverride
+
+import (
+	"flag"
+)
+
+// LevelValue is a [flag.Value] setting the level override of a handler,
+// e.g. "-log-level=debug". Levels are parsed like
+// [slog.Level.UnmarshalText].
+//
+// LevelValue also implements the Value interface of
+// github.com/spf13/pflag, so it can be used with pflag flag sets directly.
+type LevelValue struct {
+	h *OverrideHandler
+}
+
+// NewLevelValue returns a [LevelValue] setting the override of h.
+func NewLevelValue(h *OverrideHandler) *LevelValue {
+	return &LevelValue{h: h}
+}
+
+// String returns the current override, or "" if none is set.
+func (v *LevelValue) String() string {
+	if v == nil || v.h == nil {
+		return ""
+	}
+	level, ok := v.h.Level()
+	if !ok {
+		return ""
+	}
+	return level.String()
+}
+
+// Set parses s and sets it as level override, subject to the policy set
+// with [SetPolicy].
+func (v *LevelValue) Set(s string) error {
+	level, err := parseLevel(s)
+	if err != nil {
+		return err
+	}
+	return v.h.setLevel("", SourceFlag, level)
+}
+
+// Type returns the type name shown in pflag usage messages.
+func (v *LevelValue) Type() string {
+	return "level"
+}
+
+// SpecValue is a [flag.Value] applying a [Spec] to a registry, e.g.
+// "-log-level-overrides=db=debug,http=warn". The handlers named by the spec
+// must be registered before the flags are parsed.
+//
+// SpecValue also implements the Value interface of
+// github.com/spf13/pflag, so it can be used with pflag flag sets directly.
+type SpecValue struct {
+	r    *Registry
+	spec Spec
+}
+
+// NewSpecValue returns a [SpecValue] applying specs to r.
+func NewSpecValue(r *Registry) *SpecValue {
+	return &SpecValue{r: r}
+}
+
+// String returns the last spec applied.
+func (v *SpecValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.spec.String()
+}
+
+// Set parses s and applies it like [Registry.ApplySpec], recording
+// [SourceFlag] as the source of the changes.
+func (v *SpecValue) Set(s string) error {
+	spec, err := ParseSpec(s)
+	if err != nil {
+		return err
+	}
+	if _, err := v.r.applySpec(spec, SourceFlag); err != nil {
+		return err
+	}
+	v.spec = spec
+	return nil
+}
+
+// Type returns the type name shown in pflag usage messages.
+func (v *SpecValue) Type() string {
+	return "spec"
+}
+
+// RegisterFlags defines the flags "log-level", setting the override of h,
+// and "log-level-overrides", applying a [Spec] to r, in fs. Either flag is
+// left out if its h or r is nil.
+//
+// To use the flags with github.com/spf13/pflag, add fs with
+// AddGoFlagSet, or define the flags with [NewLevelValue] and
+// [NewSpecValue].
+func RegisterFlags(fs *flag.FlagSet, h *OverrideHandler, r *Registry) {
+	if h != nil {
+		fs.Var(NewLevelValue(h), "log-level", "minimum level of the logs, such as debug, info, warn or error")
+	}
+	if r != nil {
+		fs.Var(NewSpecValue(r), "log-level-overrides", "comma-separated scope=level pairs overriding the level of named loggers, such as db=debug,http=warn")
+	}
+}
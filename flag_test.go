@@ -0,0 +1,47 @@
+package slogleveloverride
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// TestRegisterFlags verifies that parsed flags set the handler and registry levels
+func TestRegisterFlags(t *testing.T) {
+	handler := New(slog.DiscardHandler)
+	registry := NewRegistry()
+	db := New(slog.DiscardHandler)
+	registry.Register("db", db)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	RegisterFlags(fs, handler, registry)
+
+	if err := fs.Parse([]string{"-log-level=warn", "-log-level-overrides", "db=debug"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if level, ok := handler.Level(); !ok || level != slog.LevelWarn {
+		t.Fatalf("handler level is (%v, %v), want (%v, true)", level, ok, slog.LevelWarn)
+	}
+	if level, ok := db.Level(); !ok || level != slog.LevelDebug {
+		t.Fatalf("db level is (%v, %v), want (%v, true)", level, ok, slog.LevelDebug)
+	}
+	if got := fs.Lookup("log-level-overrides").Value.String(); got != "db=DEBUG" {
+		t.Fatalf("log-level-overrides value is %q, want %q", got, "db=DEBUG")
+	}
+	for name, h := range map[string]*OverrideHandler{"handler": handler, "db": db} {
+		if source, _ := h.LayerSource(LayerBase); source != SourceFlag {
+			t.Errorf("%s level source is %q, want %q", name, source, SourceFlag)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-log-level=loud"},
+		{"-log-level-overrides=cache=debug"},
+	} {
+		if err := fs.Parse(args); err == nil {
+			t.Errorf("Parse(%q) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// WithForceAllWarning makes [OverrideHandler.ForceAll] send a Warn record to
+// the underlying handler if that handler still filters some levels
+// internally, since forcing the wrapper alone cannot make those records
+// appear.
+func WithForceAllWarning() Option {
+	return func(o *options) {
+		o.forceAllWarning = true
+	}
+}
+
+// ForceAll enables every level regardless of the level overrides, the global
+// level, source rules and throughput budget, until [OverrideHandler.EndForceAll]
+// is called. It is meant for short capture sessions at maximal verbosity.
+//
+// The underlying handler still receives every record but may filter them
+// itself; see [WithForceAllWarning]. A muted handler stays muted. Handlers
+// derived with WithAttrs and WithGroup are forced as well, unless created
+// with [WithIsolatedChildren].
+func (h *OverrideHandler) ForceAll() {
+	h.opts.slow.Store(true)
+	h.state.forced.Store(true)
+	if !h.opts.forceAllWarning {
+		return
+	}
+	ctx := context.Background()
+	if level := handlerLevel(ctx, h.wrapped()); level > -probeRange {
+		r := slog.NewRecord(time.Now(), slog.LevelWarn, "force-all mode enabled but the wrapped handler still filters records", 0)
+		r.AddAttrs(slog.String("wrapped_min_level", level.String()))
+		_ = h.wrapped().Handle(ctx, r)
+	}
+}
+
+// EndForceAll reverts [OverrideHandler.ForceAll].
+func (h *OverrideHandler) EndForceAll() {
+	h.state.forced.Store(false)
+}
+
+// Forced reports whether the handler is in force-all mode.
+func (h *OverrideHandler) Forced() bool {
+	return h.state.forced.Load()
+}
+
+// forceKey is the context key of [ForceCtx].
+type forceKey struct{}
+
+// forceCtxUsed is set once [ForceCtx] has been called, so that contexts are
+// only searched for the key by processes using it.
+var forceCtxUsed atomic.Bool
+
+// ForceCtx returns a copy of ctx that makes the records logged with it
+// bypass the level overrides, the global level, rules and protective
+// options such as [WithThroughputBudget] and [WithAttrRateLimit], e.g. for
+// audit events that must be emitted but share a logger with normal output:
+//
+//	logger.InfoContext(slogleveloverride.ForceCtx(ctx), "user deleted", "id", id)
+//
+// The records are passed to the underlying handler even if its own level
+// rejects them; handlers filtering in Handle may still drop them. A muted
+// handler stays muted.
+func ForceCtx(ctx context.Context) context.Context {
+	forceCtxUsed.Store(true)
+	return context.WithValue(ctx, forceKey{}, true)
+}
+
+// forcedContext reports whether ctx was returned by [ForceCtx].
+func forcedContext(ctx context.Context) bool {
+	if ctx == nil || !forceCtxUsed.Load() {
+		return false
+	}
+	forced, _ := ctx.Value(forceKey{}).(bool)
+	return forced
+}
@@ -0,0 +1,77 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestForceAll verifies that force-all mode enables every level until ended
+func TestForceAll(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug-4, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelError)
+	handler.SetLayerLevel(LayerEmergency, slog.LevelError)
+	logger := slog.New(handler)
+
+	handler.ForceAll()
+	if !handler.Forced() {
+		t.Fatal("Forced should be true after ForceAll")
+	}
+	logger.Log(context.Background(), slog.LevelDebug-4, "trace while forced")
+
+	// Mute still wins over force-all
+	handler.Mute()
+	logger.Error("dropped while muted")
+	handler.Unmute()
+
+	handler.EndForceAll()
+	logger.Warn("dropped after end")
+
+	assertHandler.AssertMessage("trace while forced")
+}
+
+// TestForceAllWarning verifies the warning when the wrapped handler still filters
+func TestForceAllWarning(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := New(assertHandler, WithForceAllWarning())
+	handler.ForceAll()
+
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "force-all mode enabled but the wrapped handler still filters records",
+		Level:   slog.LevelWarn,
+		Attrs: map[string]any{
+			"wrapped_min_level": "INFO",
+		},
+		AllAttrsMatch: true,
+	})
+}
+
+// TestForceCtx verifies that a forced context bypasses the override, the wrapped handler's level and rate limits
+func TestForceCtx(t *testing.T) {
+	var out bytes.Buffer
+	handler := NewWithLevel(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelError}), slog.LevelError,
+		WithAttrRateLimit("user", 1))
+	logger := slog.New(handler)
+	ctx := ForceCtx(t.Context())
+
+	logger.Info("dropped")
+	logger.InfoContext(ctx, "audit 1", "user", "alice")
+	logger.InfoContext(ctx, "audit 2", "user", "alice")
+	if got := out.String(); strings.Contains(got, "dropped") || !strings.Contains(got, "audit 1") || !strings.Contains(got, "audit 2") {
+		t.Errorf("output %q", got)
+	}
+
+	handler.Mute()
+	logger.InfoContext(ctx, "muted audit")
+	if strings.Contains(out.String(), "muted audit") {
+		t.Error("a forced record was written while muted")
+	}
+}
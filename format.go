@@ -0,0 +1,67 @@
+package slogleveloverride
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Output formats of a [FormatSwitcher].
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// FormatSwitcher switches the output of a handler family between
+// [slog.TextHandler] and [slog.JSONHandler] at runtime with
+// [OverrideHandler.SetHandler], e.g. to flip a service to JSON when it is
+// attached to a log pipeline. The "format" command of [ControlServer]
+// uses it.
+//
+// Each switch builds a new handler writing to the writer returned by
+// Writer, configured with the options returned by Options.
+type FormatSwitcher struct {
+	// Handler is the handler whose output is switched.
+	Handler *OverrideHandler
+	// Writer returns the destination of the new handler.
+	Writer func() io.Writer
+	// Options returns the options of the new handler. If nil, or if it
+	// returns nil, default options are used.
+	Options func() *slog.HandlerOptions
+
+	mu     sync.Mutex
+	format string
+}
+
+// SetFormat switches the output to format, [FormatText] or [FormatJSON],
+// case-insensitively.
+func (f *FormatSwitcher) SetFormat(format string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	var opts *slog.HandlerOptions
+	if f.Options != nil {
+		opts = f.Options()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch format {
+	case FormatText:
+		f.Handler.SetHandler(slog.NewTextHandler(f.Writer(), opts))
+	case FormatJSON:
+		f.Handler.SetHandler(slog.NewJSONHandler(f.Writer(), opts))
+	default:
+		return fmt.Errorf("slogleveloverride: unknown format %q", format)
+	}
+	f.format = format
+	return nil
+}
+
+// Format returns the format set with SetFormat, or the empty string if it
+// was never called.
+func (f *FormatSwitcher) Format() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.format
+}
@@ -0,0 +1,45 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestFormatSwitcher verifies that the output switches between text and JSON through the control server
+func TestFormatSwitcher(t *testing.T) {
+	var buf bytes.Buffer
+	removeTime := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey && len(groups) == 0 {
+			return slog.Attr{}
+		}
+		return a
+	}
+	handler := NewWithLevel(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: removeTime}), slog.LevelInfo)
+	switcher := &FormatSwitcher{
+		Handler: handler,
+		Writer:  func() io.Writer { return &buf },
+		Options: func() *slog.HandlerOptions { return &slog.HandlerOptions{ReplaceAttr: removeTime} },
+	}
+	server := &ControlServer{Handler: handler, Formats: switcher}
+	logger := slog.New(handler).With("component", "db")
+
+	logger.Info("as text")
+	var out bytes.Buffer
+	if err := server.ServeStream(strings.NewReader("format JSON\nformat\nformat yaml\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("as json")
+
+	wantResponses := "ok\nok json\nerror slogleveloverride: unknown format \"yaml\"\n"
+	if out.String() != wantResponses {
+		t.Errorf("responses = %q, want %q", out.String(), wantResponses)
+	}
+	want := "level=INFO msg=\"as text\" component=db\n" +
+		`{"level":"INFO","msg":"as json","component":"db"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
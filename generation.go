@@ -0,0 +1,80 @@
+package slogleveloverride
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// ErrGenerationMismatch is returned by [OverrideHandler.CompareAndSetLevel]
+// when the override changed since the expected generation.
+var ErrGenerationMismatch = errors.New("slogleveloverride: override changed since the expected generation")
+
+// Generation returns the generation of the override of h, which increases
+// with every change of the override of any [Layer]. Handlers sharing their
+// override share its generation.
+func (h *OverrideHandler) Generation() uint64 {
+	return h.state.generation.Load()
+}
+
+// CompareAndSetLevel sets the override of [LayerBase] like
+// [OverrideHandler.SetLevel], but only if the generation of the override is
+// still expectedGen, e.g. as read along with the level by a controller, so
+// that concurrent controllers do not clobber each other's changes. It
+// returns the generation after the call, along with
+// [ErrGenerationMismatch] if the override changed meanwhile or the error of
+// the policy set with [SetPolicy] if it rejected the change.
+func (h *OverrideHandler) CompareAndSetLevel(expectedGen uint64, newLevel slog.Leveler) (uint64, error) {
+	return h.compareAndSetLevel("", expectedGen, newLevel)
+}
+
+// compareAndSetLevel implements CompareAndSetLevel, consulting the policy
+// for target.
+func (h *OverrideHandler) compareAndSetLevel(target string, expectedGen uint64, newLevel slog.Leveler) (uint64, error) {
+	if newLevel == nil {
+		return h.Generation(), errors.New("slogleveloverride: nil level")
+	}
+	if err := checkPolicy(target, newLevel); err != nil {
+		return h.Generation(), err
+	}
+	gen, ok := h.state.compareAndSetLayer(LayerBase, expectedGen, SourceCode, newLevel)
+	if !ok {
+		return gen, ErrGenerationMismatch
+	}
+	h.checkMismatch(context.Background())
+	return gen, nil
+}
+
+// CompareAndSetLevel calls [OverrideHandler.CompareAndSetLevel] on the
+// handler registered under name, with the policy consulted for name. The
+// boolean is false if no handler is registered under name.
+func (r *Registry) CompareAndSetLevel(name string, expectedGen uint64, newLevel slog.Leveler) (uint64, bool, error) {
+	h, ok := r.Handler(name)
+	if !ok {
+		return 0, false, nil
+	}
+	gen, err := h.compareAndSetLevel(name, expectedGen, newLevel)
+	return gen, true, err
+}
+
+// compareAndSetLayer sets the override of layer if the generation of s is
+// expectedGen, and returns the resulting generation. A nil l removes the
+// override of layer.
+func (s *levelState) compareAndSetLayer(layer Layer, expectedGen uint64, source string, l slog.Leveler) (uint64, bool) {
+	s.mu.Lock()
+	if gen := s.generation.Load(); gen != expectedGen {
+		s.mu.Unlock()
+		return gen, false
+	}
+	if l == nil {
+		delete(s.layers, layer)
+		delete(s.sources, layer)
+	} else {
+		s.putLayer(layer, source, l)
+	}
+	top, watchers := s.republish()
+	gen := s.generation.Load()
+	s.mu.Unlock()
+	notify(watchers, top)
+	return gen, true
+}
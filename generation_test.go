@@ -0,0 +1,56 @@
+package slogleveloverride
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+// TestCompareAndSetLevel verifies that a change based on a stale generation is rejected
+func TestCompareAndSetLevel(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	gen := handler.Generation()
+
+	next, err := handler.CompareAndSetLevel(gen, slog.LevelDebug)
+	if err != nil || next <= gen {
+		t.Fatalf("CompareAndSetLevel = %d, %v, want a generation above %d", next, err, gen)
+	}
+	if got, err := handler.CompareAndSetLevel(gen, slog.LevelError); !errors.Is(err, ErrGenerationMismatch) || got != next {
+		t.Errorf("CompareAndSetLevel with a stale generation = %d, %v, want %d, ErrGenerationMismatch", got, err, next)
+	}
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Errorf("level = %v, want DEBUG", level)
+	}
+
+	handler.SetLayerLevel(LayerEmergency, slog.LevelWarn)
+	if handler.Generation() <= next {
+		t.Error("setting another layer did not advance the generation")
+	}
+}
+
+// TestRegistryCompareAndSetLevel verifies the registry variant and its policy check
+func TestRegistryCompareAndSetLevel(t *testing.T) {
+	registry := NewRegistry()
+	db := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	registry.Register("db", db)
+
+	if _, ok, _ := registry.CompareAndSetLevel("http", 0, slog.LevelDebug); ok {
+		t.Error("CompareAndSetLevel succeeded for an unregistered name")
+	}
+	gen, ok, err := registry.CompareAndSetLevel("db", db.Generation(), slog.LevelWarn)
+	if !ok || err != nil || gen != db.Generation() {
+		t.Fatalf("CompareAndSetLevel = %d, %v, %v", gen, ok, err)
+	}
+
+	errNoDebug := errors.New("no debug")
+	SetPolicy(func(target string, level slog.Leveler) error {
+		if level.Level() < slog.LevelInfo {
+			return errNoDebug
+		}
+		return nil
+	})
+	defer SetPolicy(nil)
+	if _, _, err := registry.CompareAndSetLevel("db", gen, slog.LevelDebug); !errors.Is(err, errNoDebug) {
+		t.Errorf("err = %v, want the policy error", err)
+	}
+}
@@ -0,0 +1,35 @@
+package slogleveloverride
+
+import "log/slog"
+
+// globalState holds the process-wide override set with [SetGlobalLevel].
+var globalState = newLevelState()
+
+// SetGlobalLevel sets a process-wide level override that takes precedence
+// over the override of every [OverrideHandler], giving incident responders a
+// single switch covering all handlers of this package.
+//
+// Like [OverrideHandler.SetLevel], dynamic levelers are evaluated on each
+// logging call. A nil level, or a change rejected by the policy set with
+// [SetPolicy] for the target [GlobalTarget], is ignored.
+func SetGlobalLevel(level slog.Leveler) {
+	if level != nil && checkPolicy(GlobalTarget, level) == nil {
+		globalState.store(level)
+	}
+}
+
+// ClearGlobalLevel removes the process-wide override, returning control to
+// the override of each handler.
+func ClearGlobalLevel() {
+	globalState.clear()
+}
+
+// GlobalLevel returns the current level of the process-wide override, or
+// false if none is set.
+func GlobalLevel() (slog.Level, bool) {
+	leveler, ok := globalState.load()
+	if !ok {
+		return 0, false
+	}
+	return leveler.Level(), true
+}
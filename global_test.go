@@ -0,0 +1,35 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestSetGlobalLevel verifies that the global override takes precedence over handler overrides
+func TestSetGlobalLevel(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+	defer ClearGlobalLevel()
+
+	overridden := slog.New(NewWithLevel(assertHandler, slog.LevelError))
+	plain := slog.New(New(assertHandler))
+
+	SetGlobalLevel(slog.LevelDebug)
+	if level, ok := GlobalLevel(); !ok || level != slog.LevelDebug {
+		t.Fatalf("GlobalLevel returned (%v, %v), want (%v, true)", level, ok, slog.LevelDebug)
+	}
+	overridden.Info("info from overridden")
+	plain.Debug("debug from plain")
+
+	ClearGlobalLevel()
+	if _, ok := GlobalLevel(); ok {
+		t.Fatal("GlobalLevel should report no override after ClearGlobalLevel")
+	}
+	overridden.Info("info dropped")
+	plain.Debug("debug dropped")
+
+	assertHandler.AssertMessage("info from overridden")
+	assertHandler.AssertMessage("debug from plain")
+}
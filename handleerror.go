@@ -0,0 +1,92 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// handleErrors holds the configuration of WithHandleErrors.
+type handleErrors struct {
+	retries int
+	onError func(context.Context, slog.Record, error)
+}
+
+// errorBackoff raises the level for a while after the underlying handler
+// failed.
+type errorBackoff struct {
+	level    slog.Level
+	cooldown time.Duration
+
+	raisedUntil atomic.Int64
+}
+
+// WithHandleErrors retries records the underlying handler fails to handle
+// up to retries times, and passes the final error, with the record, to
+// onError if not nil. The error is still returned by Handle, but since most
+// logging calls ignore it, onError is where failures can be noticed.
+func WithHandleErrors(retries int, onError func(ctx context.Context, record slog.Record, err error)) Option {
+	return func(o *options) {
+		o.handleErrors = &handleErrors{retries: max(retries, 0), onError: onError}
+	}
+}
+
+// WithErrorBackoff suppresses records below level for the cooldown duration
+// whenever the underlying handler fails to handle a record, reducing the
+// volume sent to a struggling sink. It applies after the retries of
+// [WithHandleErrors], if any.
+func WithErrorBackoff(level slog.Level, cooldown time.Duration) Option {
+	return func(o *options) {
+		o.errorBackoff = &errorBackoff{level: level, cooldown: cooldown}
+	}
+}
+
+// suppresses reports whether a record at level is currently suppressed
+// because of a recent error.
+func (b *errorBackoff) suppresses(level slog.Level) bool {
+	until := b.raisedUntil.Load()
+	return until != 0 && level < b.level && time.Now().UnixNano() < until
+}
+
+// forward sends record to the underlying handler, applying the error
+// handling options and the fallback of [WithFallback].
+func (h *OverrideHandler) forward(ctx context.Context, record slog.Record) error {
+	fb := h.opts.fallback
+	if fb != nil && fb.bypasses() {
+		return h.handleFallback(ctx, record)
+	}
+	err := h.wrapped().Handle(ctx, record)
+	if err != nil {
+		err = h.handleError(ctx, record, err)
+	}
+	if fb == nil {
+		return err
+	}
+	if err != nil {
+		fb.fail()
+		return h.handleFallback(ctx, record)
+	}
+	fb.down.Store(false)
+	return nil
+}
+
+// handleError retries record after the underlying handler failed with err,
+// and reports the error if the retries fail as well.
+func (h *OverrideHandler) handleError(ctx context.Context, record slog.Record, err error) error {
+	if e := h.opts.handleErrors; e != nil {
+		for i := 0; err != nil && i < e.retries; i++ {
+			err = h.wrapped().Handle(ctx, record)
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	if b := h.opts.errorBackoff; b != nil {
+		b.raisedUntil.Store(time.Now().Add(b.cooldown).UnixNano())
+	}
+	if e := h.opts.handleErrors; e != nil && e.onError != nil {
+		e.onError(ctx, record, err)
+	}
+	return err
+}
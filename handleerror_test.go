@@ -0,0 +1,81 @@
+package slogleveloverride
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// failingHandler fails the first failures calls to Handle. Handlers derived
+// with WithAttrs count their calls in the handler they were derived from.
+type failingHandler struct {
+	slog.Handler
+	failures int
+	calls    int
+	parent   *failingHandler
+}
+
+var errSinkDown = errors.New("sink down")
+
+func (h *failingHandler) Handle(ctx context.Context, record slog.Record) error {
+	root := h
+	for root.parent != nil {
+		root = root.parent
+	}
+	root.calls++
+	if root.calls <= root.failures {
+		return errSinkDown
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *failingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &failingHandler{Handler: h.Handler.WithAttrs(attrs), parent: h}
+}
+
+// TestHandleErrorsRetry verifies that failed records are retried before reporting
+func TestHandleErrorsRetry(t *testing.T) {
+	var reported []error
+	onError := func(_ context.Context, _ slog.Record, err error) {
+		reported = append(reported, err)
+	}
+
+	basic := &failingHandler{Handler: slog.DiscardHandler, failures: 2}
+	handler := New(basic, WithHandleErrors(2, onError))
+	if err := handler.Handle(t.Context(), slog.NewRecord(time.Now(), slog.LevelInfo, "retried", 0)); err != nil {
+		t.Fatalf("Handle returned %v after successful retries", err)
+	}
+
+	basic = &failingHandler{Handler: slog.DiscardHandler, failures: 3}
+	handler = New(basic, WithHandleErrors(2, onError))
+	if err := handler.Handle(t.Context(), slog.NewRecord(time.Now(), slog.LevelInfo, "failed", 0)); !errors.Is(err, errSinkDown) {
+		t.Fatalf("Handle returned %v, want %v", err, errSinkDown)
+	}
+	if basic.calls != 3 {
+		t.Errorf("underlying handler called %d times, want 3", basic.calls)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], errSinkDown) {
+		t.Errorf("reported errors are %v, want [%v]", reported, errSinkDown)
+	}
+}
+
+// TestErrorBackoff verifies that an error raises the level for the cooldown
+func TestErrorBackoff(t *testing.T) {
+	basic := &failingHandler{Handler: slog.DiscardHandler, failures: 1}
+	handler := NewWithLevel(basic, slog.LevelDebug, WithErrorBackoff(slog.LevelError, time.Hour))
+
+	_ = handler.Handle(t.Context(), slog.NewRecord(time.Now(), slog.LevelInfo, "failed", 0))
+	if handler.Enabled(t.Context(), slog.LevelWarn) {
+		t.Error("Warn should be suppressed after an error")
+	}
+	if !handler.Enabled(t.Context(), slog.LevelError) {
+		t.Error("Error should still be enabled after an error")
+	}
+
+	handler.opts.errorBackoff.raisedUntil.Store(time.Now().UnixNano())
+	if !handler.Enabled(t.Context(), slog.LevelDebug) {
+		t.Error("Debug should be enabled after the cooldown")
+	}
+}
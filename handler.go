@@ -3,7 +3,11 @@ package slogleveloverride
 import (
 	"context"
 	"log/slog"
+	"math"
+	"slices"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 var _ slog.Handler = (*OverrideHandler)(nil)
@@ -12,10 +16,26 @@ var _ slog.Handler = (*OverrideHandler)(nil)
 //
 // Initially, no level override is set, and the underlying handler's
 // Enabled method will be used to determine if logging is enabled.
-func New(h slog.Handler) *OverrideHandler {
+//
+// If h is nil, records are discarded with [slog.DiscardHandler], while level
+// overrides and queries keep working. This suits libraries with optional
+// logging.
+func New(h slog.Handler, opts ...Option) *OverrideHandler {
+	if h == nil {
+		h = slog.DiscardHandler
+	}
+	o := newOptions(opts)
+	o.root = h
+	var fallback slog.Handler
+	if o.fallback != nil {
+		fallback = o.fallback.handler
+	}
 	return &OverrideHandler{
-		basic:         h,
-		assignedLevel: &atomic.Value{},
+		basic:    h,
+		fallback: fallback,
+		side:     o.sideSink,
+		state:    newLevelState(),
+		opts:     o,
 	}
 }
 
@@ -23,8 +43,8 @@ func New(h slog.Handler) *OverrideHandler {
 // with the specified [slog.Leveler] already set.
 //
 // The level is evaluated dynamically, allowing for runtime level changes.
-func NewWithLevel(h slog.Handler, level slog.Leveler) *OverrideHandler {
-	dynamicHandler := New(h)
+func NewWithLevel(h slog.Handler, level slog.Leveler, opts ...Option) *OverrideHandler {
+	dynamicHandler := New(h, opts...)
 	dynamicHandler.SetLevel(level)
 	return dynamicHandler
 }
@@ -45,9 +65,143 @@ func NewLoggerWithLevel(logger *slog.Logger, level slog.Leveler) *slog.Logger {
 // When a level override is set via [SetLevel], the handler will evaluate the
 // [slog.Leveler] on each logging operation, enabling runtime level changes.
 // If no override is set, the handler delegates to the wrapped handler's Enabled method.
+//
+// Handlers derived via WithAttrs and WithGroup share the level override of
+// their parent, including changes made after they were derived.
 type OverrideHandler struct {
-	basic         slog.Handler
-	assignedLevel *atomic.Value
+	basic slog.Handler
+	state *levelState
+	opts  *options
+
+	// derivations are the WithAttrs and WithGroup calls that derived basic
+	// from the handler passed to New, and rebuilt is basic as rebuilt
+	// after [OverrideHandler.SetHandler].
+	derivations []func(slog.Handler) slog.Handler
+	rebuilt     atomic.Pointer[replacement]
+
+	// fallback is the handler of WithFallback, and side the handler of
+	// WithSideSink, with the same attributes and groups as basic.
+	fallback slog.Handler
+	side     slog.Handler
+
+	// attrs are the attributes added with WithAttrs before any group, used
+	// by features keyed by attribute values.
+	attrs   []slog.Attr
+	grouped bool
+
+	// attrCount and groups count the attributes and groups added with
+	// WithAttrs and WithGroup, for [OverrideHandler.Describe].
+	attrCount int
+	groups    int
+}
+
+const (
+	// levelUnset marks a state without any override.
+	levelUnset int64 = math.MinInt64
+	// levelDynamic marks a state whose override is a dynamic [slog.Leveler]
+	// stored in levelState.leveler.
+	levelDynamic int64 = math.MinInt64 + 1
+)
+
+// levelState holds the override of an [OverrideHandler].
+//
+// Overrides are set per [Layer], and the one of the highest layer is
+// published for Enabled. A static [slog.Level] is published in level so that
+// Enabled only needs a single atomic load. Any other [slog.Leveler] is
+// published in leveler and level is set to levelDynamic.
+type levelState struct {
+	level   atomic.Int64
+	leveler atomic.Pointer[levelerBox]
+	muted   atomic.Bool
+	forced  atomic.Bool
+
+	// generation counts the changes of the override, under mu.
+	generation atomic.Uint64
+
+	mu        sync.Mutex
+	layers    map[Layer]slog.Leveler
+	sources   map[Layer]string
+	watchers  map[uint64]func(slog.Leveler)
+	nextWatch uint64
+	schedule  *schedule
+	panicGen  uint64
+
+	maintenance maintenanceWindows
+}
+
+// levelerBox holds a dynamic [slog.Leveler]. Boxing the interface lets
+// levelers of any concrete type be stored in the same atomic pointer.
+type levelerBox struct {
+	slog.Leveler
+}
+
+func newLevelState() *levelState {
+	s := &levelState{}
+	s.level.Store(levelUnset)
+	return s
+}
+
+// clear removes the override of [LayerBase].
+func (s *levelState) clear() {
+	s.clearLayer(LayerBase)
+}
+
+// store sets the override of [LayerBase].
+func (s *levelState) store(l slog.Leveler) {
+	s.setLayer(LayerBase, SourceCode, l)
+}
+
+// publish makes l the override used by Enabled, or removes the override if
+// l is nil. s.mu must be held.
+func (s *levelState) publish(l slog.Leveler) {
+	if l == nil {
+		s.level.Store(levelUnset)
+		return
+	}
+	if static, ok := l.(slog.Level); ok {
+		s.level.Store(int64(static))
+		return
+	}
+	// The leveler must be visible before the marker is.
+	s.leveler.Store(&levelerBox{Leveler: l})
+	s.level.Store(levelDynamic)
+}
+
+// enabled compares level with the override, or reports false as second
+// value if no override is set.
+func (s *levelState) enabled(level slog.Level) (enabled, ok bool) {
+	switch l := s.level.Load(); l {
+	case levelUnset:
+		return false, false
+	case levelDynamic:
+		return level >= s.leveler.Load().Level(), true
+	default:
+		return int64(level) >= l, true
+	}
+}
+
+// load returns the current override, or false if none is set.
+func (s *levelState) load() (slog.Leveler, bool) {
+	switch l := s.level.Load(); l {
+	case levelUnset:
+		return nil, false
+	case levelDynamic:
+		return s.leveler.Load().Leveler, true
+	default:
+		return slog.Level(l), true
+	}
+}
+
+// snapshot returns a new state holding the current value of the override of s
+// as a static level.
+func (s *levelState) snapshot() *levelState {
+	c := newLevelState()
+	if leveler, ok := s.load(); ok {
+		c.store(leveler.Level())
+	}
+	c.muted.Store(s.muted.Load())
+	c.forced.Store(s.forced.Load())
+	return c
 }
 
 // SetLevel sets the level of an [slog.Handler] with the provided [slog.Leveler].
@@ -56,67 +210,397 @@ type OverrideHandler struct {
 // allowing for runtime level changes. This supports dynamic level implementations
 // where the Level() method may return different values over time.
 //
-// Returns true if the operation was successful and false if the provided
-// [slog.Handler] is not an [OverrideHandler] or if newLevel is nil.
+// If h is not an [OverrideHandler], the chain of wrapped handlers is searched
+// with [FindOverrideHandler].
+//
+// Returns true if the operation was successful and false if no
+// [OverrideHandler] could be found, if newLevel is nil or if the change was
+// rejected by the policy set with [SetPolicy].
 func SetLevel(h slog.Handler, newLevel slog.Leveler) bool {
-	if dlh, ok := h.(*OverrideHandler); ok && newLevel != nil {
-		dlh.SetLevel(newLevel)
-		return true
+	if dlh := FindOverrideHandler(h); dlh != nil && newLevel != nil {
+		return dlh.setLevel("", SourceCode, newLevel) == nil
 	}
 	return false
 }
 
+// maxUnwrapDepth bounds the search of [FindOverrideHandler] so that a handler
+// which returns itself cannot cause an endless loop.
+const maxUnwrapDepth = 64
+
+// FindOverrideHandler returns the first [OverrideHandler] in the chain of
+// handlers starting at h, or nil if there is none.
+//
+// Wrapping handlers are traversed through either an Unwrap() slog.Handler or
+// a Handler() slog.Handler method, the conventions used by most middleware.
+func FindOverrideHandler(h slog.Handler) *OverrideHandler {
+	for range maxUnwrapDepth {
+		switch w := h.(type) {
+		case nil:
+			return nil
+		case *OverrideHandler:
+			return w
+		case interface{ Unwrap() slog.Handler }:
+			h = w.Unwrap()
+		case interface{ Handler() slog.Handler }:
+			h = w.Handler()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
 // SetLevel sets a new level override for this handler.
 //
 // The provided [slog.Leveler] is stored and evaluated dynamically on each
 // logging call, allowing the level to change at runtime. This method is
 // thread-safe and can be called concurrently.
+//
+// The override is set on [LayerBase]; overrides set on higher layers with
+// [OverrideHandler.SetLayerLevel] take precedence. If the change is rejected
+// by the policy set with [SetPolicy], the override is left unchanged.
 func (h *OverrideHandler) SetLevel(newLevel slog.Leveler) {
-	h.assignedLevel.Store(newLevel)
+	_ = h.setLevel("", SourceCode, newLevel)
+}
+
+// setLevel sets the override of [LayerBase], changed by source, if the
+// policy accepts the change for target.
+func (h *OverrideHandler) setLevel(target, source string, newLevel slog.Leveler) error {
+	if err := checkPolicy(target, newLevel); err != nil {
+		return err
+	}
+	h.storeLevel(source, newLevel)
+	return nil
+}
+
+// storeLevel sets the override of [LayerBase], changed by source, without
+// consulting the policy, e.g. to restore an earlier override. It returns the
+// generation of the change.
+func (h *OverrideHandler) storeLevel(source string, newLevel slog.Leveler) uint64 {
+	gen := h.state.setLayer(LayerBase, source, newLevel)
+	h.checkMismatch(context.Background())
+	return gen
+}
+
+// ClearLevel removes the level override set with SetLevel.
+//
+// Unless an override is set on another [Layer], the underlying handler's
+// Enabled method is used again to determine if logging is enabled. This
+// method is thread-safe and can be called concurrently.
+func (h *OverrideHandler) ClearLevel() {
+	h.state.clear()
+	h.checkMismatch(context.Background())
+}
+
+// HasOverride reports whether a level override is currently set.
+func (h *OverrideHandler) HasOverride() bool {
+	return h.state.level.Load() != levelUnset
+}
+
+// Leveler returns the [slog.Leveler] of the override in effect, the one of
+// the highest [Layer], or false if no override is set.
+func (h *OverrideHandler) Leveler() (slog.Leveler, bool) {
+	return h.state.load()
+}
+
+// Level returns the current level of the override, evaluating dynamic
+// levelers, or false if no override is set.
+//
+// The boolean distinguishes a handler without override from one whose
+// override is [slog.LevelInfo], the zero level.
+func (h *OverrideHandler) Level() (slog.Level, bool) {
+	leveler, ok := h.state.load()
+	if !ok {
+		return 0, false
+	}
+	return leveler.Level(), true
+}
+
+// Unwrap returns the handler wrapped by this [OverrideHandler].
+func (h *OverrideHandler) Unwrap() slog.Handler {
+	return h.wrapped()
 }
 
-// Handle forwards the record to the underlying handler without modification.
+// Handle forwards the record to the underlying handler.
+//
+// Before forwarding, levels are rewritten according to
+// [OverrideHandler.SetRemapRules], and records are dropped if rejected by
+// rules set with [OverrideHandler.SetRules] or source rules set with
+// [OverrideHandler.SetSourceRules], if below the
+// threshold with [WithHandleFiltering], [WithSuppressedDigest],
+// [WithSuppressedBuffer] or [WithTraceBuffer], if over the limit of
+// [WithAttrRateLimit], or if a repeat not due with [WithRepeatBackoff].
+// Forwarded records are annotated as configured with
+// [WithOverrideAnnotation], [WithThresholdAttr] and [WithDiagnosticStacks],
+// and errors of the underlying handler are handled as configured with
+// [WithHandleErrors], [WithErrorBackoff] and [WithFallback]. With
+// [WithAsync], records are queued rather than forwarded right away. Nothing
+// is forwarded while the handler is muted; otherwise, records logged with a
+// context from [ForceCtx] are always forwarded.
 func (h *OverrideHandler) Handle(ctx context.Context, record slog.Record) error {
-	return h.basic.Handle(ctx, record)
+	if h.state.muted.Load() {
+		return nil
+	}
+	h.emitDigest(ctx)
+	h.checkMismatchPeriodically(ctx)
+	h.checkDisk(ctx)
+	record, remapped := h.remap(record)
+	if !h.guard(ctx, record.Level, func() bool { return h.allows(ctx, record, remapped) }) {
+		h.handleSuppressed(ctx, record)
+		return nil
+	}
+	forced := forcedContext(ctx)
+	if !forced {
+		if h.rateLimited(record) {
+			return nil
+		}
+		var repeated bool
+		if record, repeated = h.repeated(ctx, record); repeated {
+			return nil
+		}
+	}
+	h.flushTrace(ctx, record)
+	if h.countThroughput(ctx, record.Level) && !forced {
+		return nil
+	}
+	if attrs := h.annotations(ctx, record); len(attrs) > 0 {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+	if q := h.opts.async; q != nil {
+		if !forced && q.sheds(record.Level) {
+			return nil
+		}
+		return q.enqueue(ctx, h, record)
+	}
+	return h.forward(ctx, record)
+}
+
+// annotations returns the attributes to add to record before forwarding it.
+func (h *OverrideHandler) annotations(ctx context.Context, record slog.Record) []slog.Attr {
+	var attrs []slog.Attr
+	if h.opts.overriddenKey != "" && !h.wrapped().Enabled(ctx, record.Level) {
+		attrs = append(attrs, slog.Bool(h.opts.overriddenKey, true))
+	}
+	if h.opts.thresholdKey == "" && h.opts.stackKey == "" {
+		return attrs
+	}
+	threshold := h.effectiveLevel(ctx)
+	if h.opts.thresholdKey != "" {
+		attrs = append(attrs, slog.String(h.opts.thresholdKey, threshold.String()))
+	}
+	if stack, ok := h.stackAttr(threshold, record); ok {
+		attrs = append(attrs, stack)
+	}
+	return attrs
+}
+
+// allows makes the final decision on forwarding record in Handle, for the
+// cases in which Enabled alone cannot decide, such as a record whose level
+// was remapped.
+func (h *OverrideHandler) allows(ctx context.Context, record slog.Record, remapped bool) bool {
+	if h.state.forced.Load() || forcedContext(ctx) {
+		return true
+	}
+	if h.recordAllowed(record) {
+		return true
+	}
+	if r, ok := h.matchRule(record); ok {
+		return r.allows(ctx, h, record)
+	}
+	rs := h.opts.sourceRules.Load()
+	if rs != nil {
+		if r, ok := rs.match(record.PC); ok {
+			return record.Level >= r.Level.Level()
+		}
+	}
+	if rs != nil || remapped || h.opts.rules.Load() != nil || h.opts.allowlist != nil || h.opts.handleFiltering || h.opts.digest != nil ||
+		h.opts.traceBuffer != nil || h.opts.suppressed != nil || h.side != nil {
+		return h.enabled(ctx, record.Level)
+	}
+	return true
 }
 
 // Enabled determines if logging is enabled for the given level.
 //
-// If a level override is set, it evaluates the [slog.Leveler] dynamically
-// to get the current threshold level. If no override is set, it delegates
-// to the underlying handler's Enabled method.
+// If a static [slog.Level] override is set, it is compared without any
+// allocation or interface call. A dynamic [slog.Leveler] is evaluated on each
+// call to get the current threshold level. If no override is set, it delegates
+// to the underlying handler's Enabled method. A level set with
+// [SetGlobalLevel] takes precedence over all of them, and a level carried by
+// the context is honored with [WithContextLevels]. A handler in force-all
+// mode reports true and a muted handler false, regardless. See
+// [WithPanicRecovery] for levelers that may panic.
 func (h *OverrideHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	leveler := h.assignedLevel.Load()
-	if leveler == nil {
-		return h.basic.Enabled(ctx, level)
+	if h.opts.slow.Load() || forceCtxUsed.Load() || globalState.level.Load() != levelUnset {
+		return h.enabledSlow(ctx, level)
+	}
+	if l := h.state.level.Load(); l != levelUnset && l != levelDynamic {
+		return int64(level) >= l
+	}
+	if enabled, ok := h.state.enabled(level); ok {
+		return enabled
 	}
-	return level >= leveler.(slog.Leveler).Level()
+	return h.wrapped().Enabled(ctx, level)
+}
+
+// enabledSlow is Enabled for handlers that must consider more than their
+// override and the underlying handler, as recorded in options.slow.
+func (h *OverrideHandler) enabledSlow(ctx context.Context, level slog.Level) bool {
+	if h.state.muted.Load() {
+		return false
+	}
+	if h.opts.panicRecovery {
+		return h.guard(ctx, level, func() bool { return h.enabledAny(ctx, level) })
+	}
+	return h.enabledAny(ctx, level)
+}
+
+// enabledAny reports whether any of the threshold, rules, source rules, the
+// debug allowlist and the options inspecting suppressed records needs
+// records at level.
+func (h *OverrideHandler) enabledAny(ctx context.Context, level slog.Level) bool {
+	return h.enabled(ctx, level) || h.sourceEnabled(level) || h.ruleEnabled(level) ||
+		(h.opts.allowlist != nil && h.opts.allowlist.attrEnabled(level)) || h.opts.digest != nil ||
+		h.opts.suppressed != nil || (h.side != nil && h.side.Enabled(ctx, level)) ||
+		(h.opts.traceBuffer != nil && h.opts.traceBuffer.traced(ctx))
+}
+
+// enabled compares level with the threshold of the global override, of the
+// handler's override, or of the underlying handler, in that order of
+// precedence.
+func (h *OverrideHandler) enabled(ctx context.Context, level slog.Level) bool {
+	if h.state.forced.Load() || forcedContext(ctx) {
+		return true
+	}
+	if h.opts.deadlineMargin > 0 && h.pastDeadlineMargin(ctx, level) {
+		return false
+	}
+	if h.opts.throughput != nil && h.opts.throughput.suppresses(level) {
+		return false
+	}
+	if h.opts.errorBackoff != nil && h.opts.errorBackoff.suppresses(level) {
+		return false
+	}
+	if h.opts.async != nil && h.opts.async.sheds(level) {
+		return false
+	}
+	if h.opts.diskGuard != nil && h.diskSuppresses(ctx, level) {
+		return false
+	}
+	if h.opts.contextLevels && contextEnabled(ctx, level) {
+		return true
+	}
+	if h.opts.allowlist != nil && h.opts.allowlist.contextAllowed(ctx, level) {
+		return true
+	}
+	if enabled, ok := globalState.enabled(level); ok {
+		return enabled
+	}
+	if enabled, ok := h.state.enabled(level); ok {
+		return enabled
+	}
+	return h.wrapped().Enabled(ctx, level)
+}
+
+// pastDeadlineMargin reports whether a record at level must be suppressed
+// because the deadline of ctx is closer than the margin of
+// [WithDeadlineGuard].
+func (h *OverrideHandler) pastDeadlineMargin(ctx context.Context, level slog.Level) bool {
+	if level >= h.opts.deadlineLevel || ctx == nil {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < h.opts.deadlineMargin
 }
 
 // WithAttrs returns a new [OverrideHandler] with the given attributes added.
 //
 // The new handler shares the same level override as the parent handler,
-// meaning changes to the level will be reflected in both handlers.
+// meaning changes to the level will be reflected in both handlers, unless
+// the handler was created with [WithIsolatedChildren]. If attrs is empty, the
+// receiver itself is returned.
 func (h *OverrideHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	newLevel := &atomic.Value{}
-	newLevel.Store(h.assignedLevel.Load())
-
-	return &OverrideHandler{
-		basic:         h.basic.WithAttrs(attrs),
-		assignedLevel: newLevel,
+	if len(attrs) == 0 {
+		return h
+	}
+	d := h.derive(func(basic slog.Handler) slog.Handler { return basic.WithAttrs(attrs) })
+	if h.fallback != nil {
+		d.fallback = h.fallback.WithAttrs(attrs)
+	}
+	if h.side != nil {
+		d.side = h.side.WithAttrs(attrs)
 	}
+	if !h.grouped {
+		d.attrs = append(slices.Clip(h.attrs), attrs...)
+	}
+	d.attrCount += len(attrs)
+	return d
 }
 
 // WithGroup returns a new [OverrideHandler] with the given group name added.
 //
 // The new handler shares the same level override as the parent handler,
-// meaning changes to the level will be reflected in both handlers.
+// meaning changes to the level will be reflected in both handlers, unless
+// the handler was created with [WithIsolatedChildren]. If name is empty, the
+// receiver itself is returned.
 func (h *OverrideHandler) WithGroup(name string) slog.Handler {
-	newLevel := &atomic.Value{}
-	newLevel.Store(h.assignedLevel.Load())
+	if name == "" {
+		return h
+	}
+	d := h.derive(func(basic slog.Handler) slog.Handler { return basic.WithGroup(name) })
+	if h.fallback != nil {
+		d.fallback = h.fallback.WithGroup(name)
+	}
+	if h.side != nil {
+		d.side = h.side.WithGroup(name)
+	}
+	d.grouped = true
+	d.groups++
+	return d
+}
 
+// derive returns a new [OverrideHandler] wrapping the wrapped handler of h
+// transformed by derivation, with the level state inherited from h. The
+// state itself is shared, so later calls to SetLevel on any handler of the
+// family are observed by all of them. The derivation is recorded so that it
+// can be replayed by [OverrideHandler.SetHandler].
+func (h *OverrideHandler) derive(derivation func(slog.Handler) slog.Handler) *OverrideHandler {
+	state := h.state
+	if h.opts.isolatedChildren {
+		state = h.state.snapshot()
+	}
 	return &OverrideHandler{
-		basic:         h.basic.WithGroup(name),
-		assignedLevel: newLevel,
+		basic:       derivation(h.wrapped()),
+		derivations: append(slices.Clip(h.derivations), derivation),
+		state:       state,
+		opts:        h.opts,
+		attrs:       h.attrs,
+		grouped:     h.grouped,
+		attrCount:   h.attrCount,
+		groups:      h.groups,
+	}
+}
+
+// lookupAttr returns the value of the top-level attribute key of record, or
+// of the attributes added to the handler with WithAttrs before any group.
+func (h *OverrideHandler) lookupAttr(record slog.Record, key string) (slog.Value, bool) {
+	var value slog.Value
+	found := false
+	record.Attrs(func(a slog.Attr) bool {
+		if a.Key == key {
+			value, found = a.Value, true
+			return false
+		}
+		return true
+	})
+	if found {
+		return value, true
+	}
+	for i := len(h.attrs) - 1; i >= 0; i-- {
+		if h.attrs[i].Key == key {
+			return h.attrs[i].Value, true
+		}
 	}
+	return slog.Value{}, false
 }
@@ -1,9 +1,14 @@
 package slogleveloverride
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"log/slog"
 	"sync/atomic"
 	"testing"
+	"testing/slogtest"
+	"time"
 
 	"github.com/thejerf/slogassert"
 )
@@ -308,3 +313,463 @@ func TestConcurrentSetLevel(t *testing.T) {
 	assertHandler.AssertMessage("concurrent message")
 	assertHandler.AssertMessage("concurrent message")
 }
+
+// TestSwitchStaticAndDynamicLevel verifies that static and dynamic overrides can replace each other
+func TestSwitchStaticAndDynamicLevel(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelError)
+	logger := slog.New(handler)
+
+	// Switch from a static level to a dynamic one
+	dynamicLvl := newDynamicLevel(slog.LevelWarn)
+	handler.SetLevel(dynamicLvl)
+	logger.Info("info 1")
+	logger.Warn("warn 1")
+
+	// And back to a static level
+	handler.SetLevel(slog.LevelInfo)
+	dynamicLvl.SetLevel(slog.LevelError)
+	logger.Info("info 2")
+
+	assertHandler.AssertMessage("warn 1")
+	assertHandler.AssertMessage("info 2")
+}
+
+// TestEnabledDoesNotAllocate verifies that Enabled with a static level does not allocate
+func TestEnabledDoesNotAllocate(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelWarn)
+	ctx := context.Background()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		handler.Enabled(ctx, slog.LevelInfo)
+	})
+	if allocs != 0 {
+		t.Fatalf("Enabled allocated %v times, want 0", allocs)
+	}
+}
+
+// valueCountingContext counts the lookups of context values.
+type valueCountingContext struct {
+	context.Context
+	lookups int
+}
+
+func (c *valueCountingContext) Value(key any) any {
+	c.lookups++
+	return c.Context.Value(key)
+}
+
+// TestEnabledFastPath verifies that Enabled of a handler without options only consults its override
+func TestEnabledFastPath(t *testing.T) {
+	used := forceCtxUsed.Swap(false)
+	t.Cleanup(func() { forceCtxUsed.Store(used) })
+
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	ctx := &valueCountingContext{Context: context.Background()}
+	if handler.Enabled(ctx, slog.LevelInfo) || !handler.Enabled(ctx, slog.LevelWarn) {
+		t.Error("Enabled did not honor the override")
+	}
+	if handler.opts.slow.Load() || ctx.lookups != 0 {
+		t.Errorf("Enabled took the slow path, with %d context lookups", ctx.lookups)
+	}
+
+	handler.SetRules(Rule{Level: slog.LevelDebug})
+	if !handler.opts.slow.Load() {
+		t.Error("rules did not disable the fast path")
+	}
+	ForceCtx(ctx)
+	if !forceCtxUsed.Load() {
+		t.Error("ForceCtx was not recorded")
+	}
+}
+
+// BenchmarkEnabledStatic measures Enabled with a static level override. It
+// ran at about 2 ns/op without options; a regression here usually means the
+// fast path in Enabled consults an option or the context again.
+func BenchmarkEnabledStatic(b *testing.B) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	ctx := context.Background()
+
+	for b.Loop() {
+		handler.Enabled(ctx, slog.LevelInfo)
+	}
+}
+
+// BenchmarkEnabledDynamic measures Enabled with a dynamic level override
+func BenchmarkEnabledDynamic(b *testing.B) {
+	handler := NewWithLevel(slog.DiscardHandler, newDynamicLevel(slog.LevelWarn))
+	ctx := context.Background()
+
+	for b.Loop() {
+		handler.Enabled(ctx, slog.LevelInfo)
+	}
+}
+
+// TestWithIsolatedChildren verifies that isolated derived handlers can be tuned independently
+func TestWithIsolatedChildren(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	dynamicLvl := newDynamicLevel(slog.LevelWarn)
+	handler := NewWithLevel(assertHandler, dynamicLvl, WithIsolatedChildren())
+	logger := slog.New(handler)
+
+	derivedHandler := handler.WithAttrs([]slog.Attr{slog.String("component", "isolated")}).(*OverrideHandler)
+	derivedLogger := slog.New(derivedHandler)
+
+	// Changing the parent's leveler does not affect the isolated child
+	dynamicLvl.SetLevel(slog.LevelInfo)
+	logger.Info("info from parent")
+	derivedLogger.Info("info from derived")
+
+	// Changing the child's level does not affect the parent
+	derivedHandler.SetLevel(slog.LevelError)
+	logger.Warn("warn from parent")
+	derivedLogger.Warn("warn from derived")
+
+	assertHandler.AssertMessage("info from parent")
+	assertHandler.AssertMessage("warn from parent")
+}
+
+// TestSetLevelAfterDerivation verifies that SetLevel on the parent affects previously derived handlers
+func TestSetLevelAfterDerivation(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := New(assertHandler)
+	derivedLogger := slog.New(handler).With("component", "derived")
+	groupLogger := slog.New(handler).WithGroup("mygroup")
+
+	handler.SetLevel(slog.LevelError)
+	derivedLogger.Warn("warn from derived")
+	groupLogger.Warn("warn from group")
+
+	// Setting the level on a child is observed by the parent too
+	SetLevel(derivedLogger.Handler(), slog.LevelWarn)
+	slog.New(handler).Warn("warn from parent")
+	groupLogger.Warn("warn from group")
+
+	assertHandler.AssertMessage("warn from parent")
+	assertHandler.AssertMessage("warn from group")
+}
+
+// unwrapHandler is a test middleware exposing its wrapped handler through Unwrap
+type unwrapHandler struct {
+	slog.Handler
+}
+
+func (h unwrapHandler) Unwrap() slog.Handler {
+	return h.Handler
+}
+
+// accessorHandler is a test middleware exposing its wrapped handler through Handler
+type accessorHandler struct {
+	next slog.Handler
+}
+
+func (h accessorHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h accessorHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.next.Handle(ctx, record)
+}
+
+func (h accessorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return accessorHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h accessorHandler) WithGroup(name string) slog.Handler {
+	return accessorHandler{next: h.next.WithGroup(name)}
+}
+
+func (h accessorHandler) Handler() slog.Handler {
+	return h.next
+}
+
+// TestFindOverrideHandler verifies that OverrideHandlers are found through wrapping middleware
+func TestFindOverrideHandler(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := New(assertHandler)
+	wrapped := accessorHandler{next: unwrapHandler{Handler: handler}}
+
+	if found := FindOverrideHandler(wrapped); found != handler {
+		t.Fatalf("FindOverrideHandler returned %v, want %v", found, handler)
+	}
+	if found := FindOverrideHandler(assertHandler); found != nil {
+		t.Fatalf("FindOverrideHandler returned %v for a handler without OverrideHandler", found)
+	}
+}
+
+// TestSetLevelFunctionUnwraps verifies that SetLevel reaches an OverrideHandler behind middleware
+func TestSetLevelFunctionUnwraps(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	wrapped := unwrapHandler{Handler: New(assertHandler)}
+	logger := slog.New(wrapped)
+
+	if !SetLevel(wrapped, slog.LevelWarn) {
+		t.Fatal("SetLevel should return true for a wrapped OverrideHandler")
+	}
+
+	logger.Info("info message")
+	logger.Warn("warn message")
+
+	assertHandler.AssertMessage("warn message")
+}
+
+// TestUnwrap verifies that Unwrap returns the wrapped handler
+func TestUnwrap(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := New(assertHandler)
+	if got := handler.Unwrap(); got != slog.Handler(assertHandler) {
+		t.Fatalf("Unwrap returned %v, want %v", got, assertHandler)
+	}
+}
+
+// TestClearLevel verifies that ClearLevel returns control to the underlying handler
+func TestClearLevel(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := New(assertHandler)
+	logger := slog.New(handler)
+
+	if handler.HasOverride() {
+		t.Fatal("HasOverride should be false for a new handler")
+	}
+
+	handler.SetLevel(slog.LevelError)
+	if !handler.HasOverride() {
+		t.Fatal("HasOverride should be true after SetLevel")
+	}
+	logger.Info("info 1")
+
+	handler.ClearLevel()
+	if handler.HasOverride() {
+		t.Fatal("HasOverride should be false after ClearLevel")
+	}
+	logger.Debug("debug 1")
+	logger.Info("info 2")
+
+	assertHandler.AssertMessage("info 2")
+}
+
+// TestLevelerAndLevel verifies that Leveler and Level distinguish an unset override from LevelInfo
+func TestLevelerAndLevel(t *testing.T) {
+	handler := New(slog.DiscardHandler)
+
+	if _, ok := handler.Leveler(); ok {
+		t.Fatal("Leveler should report no override for a new handler")
+	}
+	if _, ok := handler.Level(); ok {
+		t.Fatal("Level should report no override for a new handler")
+	}
+
+	handler.SetLevel(slog.LevelInfo)
+	if level, ok := handler.Level(); !ok || level != slog.LevelInfo {
+		t.Fatalf("Level returned (%v, %v), want (%v, true)", level, ok, slog.LevelInfo)
+	}
+
+	dynamicLvl := newDynamicLevel(slog.LevelWarn)
+	handler.SetLevel(dynamicLvl)
+	if leveler, ok := handler.Leveler(); !ok || leveler != slog.Leveler(dynamicLvl) {
+		t.Fatalf("Leveler returned (%v, %v), want (%v, true)", leveler, ok, dynamicLvl)
+	}
+	dynamicLvl.SetLevel(slog.LevelError)
+	if level, _ := handler.Level(); level != slog.LevelError {
+		t.Fatalf("Level returned %v, want %v", level, slog.LevelError)
+	}
+}
+
+// TestWithHandleFiltering verifies that Handle drops records below the threshold when enabled
+func TestWithHandleFiltering(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	ctx := context.Background()
+	info := slog.NewRecord(time.Now(), slog.LevelInfo, "info message", 0)
+	warn := slog.NewRecord(time.Now(), slog.LevelWarn, "warn message", 0)
+
+	// Without the option, Handle forwards every record
+	handler := NewWithLevel(assertHandler, slog.LevelWarn)
+	if err := handler.Handle(ctx, info); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	assertHandler.AssertMessage("info message")
+
+	filtering := NewWithLevel(assertHandler, slog.LevelWarn, WithHandleFiltering())
+	if err := filtering.Handle(ctx, info); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if err := filtering.Handle(ctx, warn); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	assertHandler.AssertMessage("warn message")
+}
+
+// TestWithOverrideAnnotation verifies that records passing only due to the override are annotated
+func TestWithOverrideAnnotation(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelDebug, WithOverrideAnnotation(""))
+	logger := slog.New(handler)
+
+	logger.Debug("debug message")
+	logger.Info("info message")
+
+	// slogassert escapes dots in attribute keys
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message:       "debug message",
+		Level:         slog.LevelDebug,
+		Attrs:         map[string]any{`log\.overridden`: true},
+		AllAttrsMatch: true,
+	})
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message:       "info message",
+		Level:         slog.LevelInfo,
+		AllAttrsMatch: true,
+	})
+}
+
+// TestWithThresholdAttr verifies that records carry the effective threshold
+func TestWithThresholdAttr(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := New(assertHandler, WithThresholdAttr("threshold"))
+	logger := slog.New(handler)
+
+	// Without override, the underlying handler's level is reported
+	logger.Info("info message")
+
+	handler.SetLevel(slog.LevelDebug)
+	logger.Debug("debug message")
+
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "info message",
+		Level:   slog.LevelInfo,
+		Attrs:   map[string]any{"threshold": "INFO"},
+	})
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "debug message",
+		Level:   slog.LevelDebug,
+		Attrs:   map[string]any{"threshold": "DEBUG"},
+	})
+}
+
+// TestSetLevelWithDifferentLevelerTypes verifies that levelers of different concrete types can replace each other
+func TestSetLevelWithDifferentLevelerTypes(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, newDynamicLevel(slog.LevelError))
+	logger := slog.New(handler)
+
+	var levelVar slog.LevelVar
+	levelVar.Set(slog.LevelWarn)
+	handler.SetLevel(&levelVar)
+
+	logger.Info("info message")
+	logger.Warn("warn message")
+
+	assertHandler.AssertMessage("warn message")
+}
+
+// TestWithDeadlineGuard verifies that verbose records are suppressed close to the context deadline
+func TestWithDeadlineGuard(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelDebug, WithDeadlineGuard(time.Second, slog.LevelWarn))
+	logger := slog.New(handler)
+
+	relaxed, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	logger.DebugContext(relaxed, "debug with time left")
+
+	urgent, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	logger.DebugContext(urgent, "debug dropped")
+	logger.InfoContext(urgent, "info dropped")
+	logger.WarnContext(urgent, "warn close to deadline")
+
+	logger.Debug("debug without deadline")
+
+	assertHandler.AssertMessage("debug with time left")
+	assertHandler.AssertMessage("warn close to deadline")
+	assertHandler.AssertMessage("debug without deadline")
+}
+
+// TestEmptyWithAttrsAndWithGroup verifies that empty attrs and group names return the receiver
+func TestEmptyWithAttrsAndWithGroup(t *testing.T) {
+	handler := New(slog.DiscardHandler)
+
+	if got := handler.WithAttrs(nil); got != slog.Handler(handler) {
+		t.Error("WithAttrs with no attrs should return the receiver")
+	}
+	if got := handler.WithGroup(""); got != slog.Handler(handler) {
+		t.Error("WithGroup with an empty name should return the receiver")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		handler.WithAttrs(nil)
+		handler.WithGroup("")
+	})
+	if allocs != 0 {
+		t.Fatalf("empty WithAttrs and WithGroup allocated %v times, want 0", allocs)
+	}
+}
+
+// TestNewWithNilHandler verifies that a nil handler discards records without panicking
+func TestNewWithNilHandler(t *testing.T) {
+	handler := NewWithLevel(nil, slog.LevelDebug)
+	logger := slog.New(handler).With("key", "value").WithGroup("group")
+
+	logger.Debug("discarded")
+	logger.Error("discarded")
+
+	if !handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("handler should honor the override")
+	}
+	if level, ok := handler.Level(); !ok || level != slog.LevelDebug {
+		t.Errorf("Level returned (%v, %v), want (%v, true)", level, ok, slog.LevelDebug)
+	}
+	if handler.Unwrap() != slog.DiscardHandler {
+		t.Error("Unwrap should return slog.DiscardHandler")
+	}
+}
+
+// TestSlogtest verifies that the handler passes the slogtest conformance suite
+func TestSlogtest(t *testing.T) {
+	tests := map[string][]Option{
+		"plain":     nil,
+		"filtering": {WithHandleFiltering(), WithIsolatedChildren()},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slogtest.Run(t, func(*testing.T) slog.Handler {
+				buf.Reset()
+				return NewWithLevel(slog.NewJSONHandler(&buf, nil), slog.LevelInfo, opts...)
+			}, func(t *testing.T) map[string]any {
+				var m map[string]any
+				if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+					t.Fatalf("parsing output %q: %v", buf.String(), err)
+				}
+				return m
+			})
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package slogleveloverride
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPollBody is the size limit of documents fetched by [HTTPLeveler].
+const maxPollBody = 1 << 20
+
+// HTTPLeveler is a [RemoteLeveler] polling a URL, for configuration
+// published from a plain web service or object storage such as S3.
+//
+// The document served is either a single level, such as "debug", or, if
+// OnRules is set, rules as accepted by [ParseRules] or, with a JSON content
+// type, a JSON array of [Rule]s, which are passed to OnRules. Requests carry
+// the ETag of the previous response in If-None-Match, so unchanged documents
+// are not transferred again.
+//
+// The fields must not be changed after Start.
+type HTTPLeveler struct {
+	*RemoteLeveler
+
+	// URL is the address of the document.
+	URL string
+	// Interval is the time between two polls. If zero or negative, one
+	// minute is used.
+	Interval time.Duration
+	// Jitter is the fraction of Interval by which each wait is randomly
+	// lengthened or shortened, so that many processes do not poll in step.
+	Jitter float64
+	// Client sends the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// OnRules receives the rules of rules documents. If nil, only levels
+	// are accepted.
+	OnRules func([]Rule)
+
+	mu   sync.Mutex
+	etag string
+}
+
+// NewHTTPLeveler creates an [HTTPLeveler] polling url every interval,
+// starting at initial until the first successful poll. It does not fall
+// back to a safe level; set MaxStaleness and Safe to do so.
+func NewHTTPLeveler(url string, interval time.Duration, initial slog.Level) *HTTPLeveler {
+	return &HTTPLeveler{
+		RemoteLeveler: NewRemoteLeveler(initial, 0, initial),
+		URL:           url,
+		Interval:      interval,
+	}
+}
+
+// Start polls right away and then every Interval, with jitter, until ctx is
+// done or stop is called. Failed polls are recorded with Fail, keeping the
+// last-known level.
+func (l *HTTPLeveler) Start(ctx context.Context) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		for {
+			_ = l.Poll(ctx)
+			timer := time.NewTimer(l.wait())
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+	return cancel
+}
+
+// wait returns the time until the next poll.
+func (l *HTTPLeveler) wait() time.Duration {
+	interval := l.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if l.Jitter <= 0 {
+		return interval
+	}
+	spread := (rand.Float64()*2 - 1) * l.Jitter
+	return time.Duration(float64(interval) * (1 + spread))
+}
+
+// Poll fetches the document once and applies it. The error, if any, is
+// also recorded with Fail.
+func (l *HTTPLeveler) Poll(ctx context.Context) error {
+	if err := l.poll(ctx); err != nil {
+		err = fmt.Errorf("slogleveloverride: polling %s: %w", l.URL, err)
+		l.Fail(err)
+		return err
+	}
+	return nil
+}
+
+func (l *HTTPLeveler) poll(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.URL, nil)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	etag := l.etag
+	l.mu.Unlock()
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		l.touch()
+		return nil
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPollBody))
+	if err != nil {
+		return err
+	}
+	if err := l.apply(resp.Header.Get("Content-Type"), string(body)); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.etag = resp.Header.Get("ETag")
+	l.mu.Unlock()
+	return nil
+}
+
+// apply applies a fetched document.
+func (l *HTTPLeveler) apply(contentType, body string) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return applyDocument(l.RemoteLeveler, l.OnRules, mediaType == "application/json", body)
+}
+
+// applyDocument applies a document holding a level, or rules passed to
+// onRules, to l. Rules are decoded from JSON if isJSON is set and parsed
+// with [ParseRules] otherwise.
+func applyDocument(l *RemoteLeveler, onRules func([]Rule), isJSON bool, body string) error {
+	text := strings.TrimSpace(body)
+	if level, err := parseLevel(text); err == nil {
+		l.Update(level)
+		return nil
+	}
+	if onRules == nil {
+		return fmt.Errorf("invalid level %q", text)
+	}
+	var rules []Rule
+	if isJSON {
+		if err := json.Unmarshal([]byte(text), &rules); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if rules, err = ParseRules(text); err != nil {
+			return err
+		}
+	}
+	if len(rules) == 0 {
+		return errors.New("empty rules document")
+	}
+	onRules(rules)
+	l.touch()
+	return nil
+}
@@ -0,0 +1,100 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestHTTPLeveler verifies that levels and rules are polled with ETag revalidation
+func TestHTTPLeveler(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		contentType = "text/plain"
+		document    = "debug"
+		etag        = `"v1"`
+		status      = http.StatusOK
+		revalidated int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			revalidated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", contentType)
+		w.Write([]byte(document + "\n"))
+	}))
+	defer server.Close()
+	set := func(ct, doc, tag string, code int) {
+		mu.Lock()
+		defer mu.Unlock()
+		contentType, document, etag, status = ct, doc, tag, code
+	}
+
+	var rules []Rule
+	leveler := NewHTTPLeveler(server.URL, time.Hour, slog.LevelInfo)
+	leveler.OnRules = func(rs []Rule) { rules = rs }
+
+	if err := leveler.Poll(t.Context()); err != nil || leveler.Level() != slog.LevelDebug {
+		t.Fatalf("first poll: %v, level %v", err, leveler.Level())
+	}
+	if err := leveler.Poll(t.Context()); err != nil || revalidated != 1 {
+		t.Errorf("second poll: %v, revalidated %d times", err, revalidated)
+	}
+
+	set("text/plain", "scope=db level>=debug attr.tenant=acme", `"v2"`, http.StatusOK)
+	if err := leveler.Poll(t.Context()); err != nil || len(rules) != 1 || rules[0].Scope != "db" {
+		t.Errorf("rules poll: %v, rules %+v", err, rules)
+	}
+	set("application/json", `[{"level":"WARN"},{"level":"ERROR"}]`, `"v3"`, http.StatusOK)
+	if err := leveler.Poll(t.Context()); err != nil || len(rules) != 2 || rules[1].Level != slog.LevelError {
+		t.Errorf("JSON rules poll: %v, rules %+v", err, rules)
+	}
+
+	set("", "", "", http.StatusInternalServerError)
+	if err := leveler.Poll(t.Context()); err == nil {
+		t.Error("poll of a failing server should fail")
+	}
+	if status := leveler.Status(); status.Level != slog.LevelDebug || status.Err == nil {
+		t.Errorf("status after failure = %+v", status)
+	}
+}
+
+// TestHTTPLevelerStart verifies that polling starts right away and stops
+func TestHTTPLevelerStart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("warn"))
+	}))
+	defer server.Close()
+
+	leveler := NewHTTPLeveler(server.URL, time.Millisecond, slog.LevelInfo)
+	leveler.Jitter = 0.5
+	stop := leveler.Start(t.Context())
+	defer stop()
+	waitFor(t, "first poll", func() bool { return leveler.Level() == slog.LevelWarn })
+}
+
+// TestHTTPLevelerDefaultInterval verifies that a non-positive Interval falls back to one minute
+func TestHTTPLevelerDefaultInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		leveler := NewHTTPLeveler("http://localhost", interval, slog.LevelInfo)
+		if got := leveler.wait(); got != time.Minute {
+			t.Errorf("wait with Interval %v = %v, want %v", interval, got, time.Minute)
+		}
+		leveler.Jitter = 0.5
+		if got := leveler.wait(); got < 30*time.Second || got > 90*time.Second {
+			t.Errorf("wait with Interval %v and jitter = %v, want within 50%% of %v", interval, got, time.Minute)
+		}
+	}
+}
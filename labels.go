@@ -0,0 +1,156 @@
+package slogleveloverride
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// RegisterWithLabels adds h to the registry under name, like Register, with
+// labels such as team=payments or tier=frontend, which
+// [Registry.SetLevelBySelector] selects handlers by.
+func (r *Registry) RegisterWithLabels(name string, h *OverrideHandler, labels map[string]string) {
+	r.Register(name, h)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.handlers[name] == h && len(labels) > 0 {
+		r.labels[name] = maps.Clone(labels)
+	}
+}
+
+// Labels returns the labels of the handler registered under name.
+func (r *Registry) Labels(name string) map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return maps.Clone(r.labels[name])
+}
+
+// Select returns the sorted names of the registered handlers whose labels
+// match selector, as parsed by [ParseSelector].
+func (r *Registry) Select(selector string) ([]string, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.selectNames(sel), nil
+}
+
+// SetLevelBySelector sets the level override of every registered handler
+// whose labels match selector, as parsed by [ParseSelector], e.g. to enable
+// Debug for all components of a team at once, and returns their names.
+//
+// Like [Registry.Apply], no level is changed if the selector is invalid or
+// any change is rejected by the policy set with [SetPolicy].
+func (r *Registry) SetLevelBySelector(selector string, level slog.Leveler) ([]string, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if level == nil {
+		return nil, fmt.Errorf("slogleveloverride: nil level for selector %q", selector)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := r.selectNames(sel)
+	changes := make(map[string]slog.Leveler, len(names))
+	for _, name := range names {
+		changes[name] = level
+	}
+	if _, err := r.apply(changes); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// selectNames returns the sorted names of the handlers matching sel. r.mu
+// must be held.
+func (r *Registry) selectNames(sel Selector) []string {
+	var names []string
+	for name := range r.handlers {
+		if sel.Matches(r.labels[name]) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Requirement is a single condition of a [Selector] on a label.
+type Requirement struct {
+	Key string
+	// Op is one of "=", "!=" and "exists".
+	Op    string
+	Value string
+}
+
+// Selector is a parsed label selector such as "team=payments,tier!=batch",
+// matching labels that satisfy all of its requirements.
+type Selector []Requirement
+
+// ParseSelector parses a comma-separated list of requirements, each of the
+// form key=value, key!=value or key, the latter requiring the label to be
+// present, in the style of Kubernetes equality-based selectors. An empty
+// selector matches every handler. Whitespace around requirements is
+// ignored.
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	for req := range strings.SplitSeq(s, ",") {
+		req = strings.TrimSpace(req)
+		if req == "" {
+			continue
+		}
+		var r Requirement
+		if key, value, found := strings.Cut(req, "!="); found {
+			r = Requirement{Key: key, Op: "!=", Value: value}
+		} else if key, value, found := strings.Cut(req, "="); found {
+			r = Requirement{Key: key, Op: "=", Value: strings.TrimPrefix(value, "=")}
+		} else {
+			r = Requirement{Key: req, Op: "exists"}
+		}
+		r.Key, r.Value = strings.TrimSpace(r.Key), strings.TrimSpace(r.Value)
+		if r.Key == "" {
+			return nil, fmt.Errorf("slogleveloverride: empty label key in selector %q", s)
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+// Matches reports whether labels satisfy all requirements of the selector.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		value, ok := labels[r.Key]
+		switch r.Op {
+		case "=":
+			if !ok || value != r.Value {
+				return false
+			}
+		case "!=":
+			if ok && value == r.Value {
+				return false
+			}
+		default:
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String formats the selector in the syntax accepted by [ParseSelector].
+func (s Selector) String() string {
+	reqs := make([]string, len(s))
+	for i, r := range s {
+		if r.Op == "exists" {
+			reqs[i] = r.Key
+		} else {
+			reqs[i] = r.Key + r.Op + r.Value
+		}
+	}
+	return strings.Join(reqs, ",")
+}
@@ -0,0 +1,78 @@
+package slogleveloverride
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+// TestSetLevelBySelector verifies that levels are changed for all handlers matching the labels
+func TestSetLevelBySelector(t *testing.T) {
+	registry := NewRegistry()
+	handlers := map[string]*OverrideHandler{}
+	for name, labels := range map[string]map[string]string{
+		"checkout": {"team": "payments", "tier": "frontend"},
+		"ledger":   {"team": "payments", "tier": "batch"},
+		"search":   {"team": "discovery", "tier": "frontend"},
+		"legacy":   nil,
+	} {
+		handlers[name] = NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+		registry.RegisterWithLabels(name, handlers[name], labels)
+	}
+
+	names, err := registry.SetLevelBySelector("team=payments, tier!=batch", slog.LevelDebug)
+	if err != nil || !slices.Equal(names, []string{"checkout"}) {
+		t.Fatalf("SetLevelBySelector = %v, %v", names, err)
+	}
+	if level, _ := handlers["checkout"].Level(); level != slog.LevelDebug {
+		t.Errorf("checkout level = %v, want %v", level, slog.LevelDebug)
+	}
+
+	if names, _ := registry.Select("tier"); !slices.Equal(names, []string{"checkout", "ledger", "search"}) {
+		t.Errorf("Select(tier) = %v", names)
+	}
+	if names, _ := registry.Select(""); len(names) != 4 {
+		t.Errorf("Select of the empty selector = %v", names)
+	}
+	if _, err := registry.Select("=payments"); err == nil {
+		t.Error("a requirement without key should be rejected")
+	}
+
+	errNoDebug := errors.New("no debug")
+	SetPolicy(func(target string, level slog.Leveler) error {
+		if target == "search" {
+			return errNoDebug
+		}
+		return nil
+	})
+	defer SetPolicy(nil)
+	if _, err := registry.SetLevelBySelector("tier=frontend", slog.LevelWarn); !errors.Is(err, errNoDebug) {
+		t.Errorf("SetLevelBySelector returned %v, want %v", err, errNoDebug)
+	}
+	if level, _ := handlers["checkout"].Level(); level != slog.LevelDebug {
+		t.Errorf("checkout changed despite the rejection: %v", level)
+	}
+
+	registry.Unregister("ledger")
+	if labels := registry.Labels("ledger"); labels != nil {
+		t.Errorf("labels kept after Unregister: %v", labels)
+	}
+}
+
+// TestParseSelector verifies the selector syntax and its round trip through String
+func TestParseSelector(t *testing.T) {
+	sel, err := ParseSelector(" team == payments ,tier!=batch,canary ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sel.String(); got != "team=payments,tier!=batch,canary" {
+		t.Errorf("String = %q", got)
+	}
+	if !sel.Matches(map[string]string{"team": "payments", "canary": ""}) {
+		t.Error("selector should match")
+	}
+	if sel.Matches(map[string]string{"team": "payments"}) {
+		t.Error("selector should require canary")
+	}
+}
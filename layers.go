@@ -0,0 +1,131 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// Layer is the priority at which a level override is set. Each handler holds
+// at most one override per layer, and the override of the highest layer is
+// the one in effect, so clearing a layer exposes the next lower one.
+//
+// Any value can be used; the predefined layers cover the common setup of
+// static configuration, overridden by remote configuration, overridden by
+// manual emergency changes.
+type Layer int
+
+const (
+	// LayerBase is the layer of SetLevel and ClearLevel.
+	LayerBase Layer = 0
+	// LayerRemote is meant for levels distributed by remote configuration.
+	LayerRemote Layer = 100
+	// LayerEmergency is meant for manual changes during incidents.
+	LayerEmergency Layer = 200
+)
+
+// setLayer sets the override of layer, changed by source, publishes the
+// resulting override and notifies the watchers. It returns the generation
+// of the change.
+func (s *levelState) setLayer(layer Layer, source string, l slog.Leveler) uint64 {
+	s.mu.Lock()
+	s.putLayer(layer, source, l)
+	top, watchers := s.republish()
+	gen := s.generation.Load()
+	s.mu.Unlock()
+	notify(watchers, top)
+	return gen
+}
+
+// clearLayer removes the override of layer, publishes the resulting
+// override and notifies the watchers.
+func (s *levelState) clearLayer(layer Layer) {
+	s.mu.Lock()
+	delete(s.layers, layer)
+	delete(s.sources, layer)
+	top, watchers := s.republish()
+	s.mu.Unlock()
+	notify(watchers, top)
+}
+
+// putLayer records the override of layer and its source. s.mu must be held.
+func (s *levelState) putLayer(layer Layer, source string, l slog.Leveler) {
+	if s.layers == nil {
+		s.layers = make(map[Layer]slog.Leveler)
+		s.sources = make(map[Layer]string)
+	}
+	s.layers[layer] = l
+	s.sources[layer] = source
+}
+
+// republish publishes the override of the highest layer, advances the
+// generation and returns the override along with the watchers to notify
+// once s.mu is released. s.mu must be held.
+func (s *levelState) republish() (slog.Leveler, []func(slog.Leveler)) {
+	top := s.top()
+	s.publish(top)
+	s.generation.Add(1)
+	return top, slices.Collect(maps.Values(s.watchers))
+}
+
+// layer returns the override of layer.
+func (s *levelState) layer(layer Layer) (slog.Leveler, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.layers[layer]
+	return l, ok
+}
+
+// top returns the override of the highest layer, or nil. s.mu must be held.
+func (s *levelState) top() slog.Leveler {
+	var (
+		top   slog.Leveler
+		found bool
+		best  Layer
+	)
+	for layer, l := range s.layers {
+		if !found || layer > best {
+			top, best, found = l, layer, true
+		}
+	}
+	return top
+}
+
+// SetLayerLevel sets the level override of layer. A nil level, or a change
+// rejected by the policy set with [SetPolicy], is ignored.
+//
+// The override of the highest layer with an override is the one in effect.
+// SetLevel is equivalent to SetLayerLevel with [LayerBase].
+func (h *OverrideHandler) SetLayerLevel(layer Layer, level slog.Leveler) {
+	h.setLayerLevel(layer, SourceCode, level)
+}
+
+// setLayerLevel implements SetLayerLevel, recording source as the source
+// of the change.
+func (h *OverrideHandler) setLayerLevel(layer Layer, source string, level slog.Leveler) {
+	if level != nil && checkPolicy("", level) == nil {
+		h.state.setLayer(layer, source, level)
+		h.checkMismatch(context.Background())
+	}
+}
+
+// ClearLayer removes the level override of layer, exposing the override of
+// the next lower layer, if any.
+func (h *OverrideHandler) ClearLayer(layer Layer) {
+	h.state.clearLayer(layer)
+	h.checkMismatch(context.Background())
+}
+
+// LayerLeveler returns the level override of layer, or false if layer has
+// none.
+func (h *OverrideHandler) LayerLeveler(layer Layer) (slog.Leveler, bool) {
+	return h.state.layer(layer)
+}
+
+// Layers returns the level overrides of all layers that have one.
+func (h *OverrideHandler) Layers() map[Layer]slog.Leveler {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	return maps.Clone(h.state.layers)
+}
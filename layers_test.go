@@ -0,0 +1,50 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestLayers verifies that the highest layer wins and clearing it exposes the next one
+func TestLayers(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelWarn)
+	logger := slog.New(handler)
+
+	handler.SetLayerLevel(LayerEmergency, slog.LevelDebug)
+	handler.SetLayerLevel(LayerRemote, slog.LevelError)
+	logger.Debug("debug from emergency")
+
+	// Setting the base layer does not affect the layer in effect
+	handler.SetLevel(slog.LevelInfo)
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Fatalf("Level = %v, want %v", level, slog.LevelDebug)
+	}
+
+	handler.ClearLayer(LayerEmergency)
+	logger.Warn("warn dropped by remote")
+	logger.Error("error from remote")
+
+	handler.ClearLayer(LayerRemote)
+	logger.Info("info from base")
+
+	if leveler, ok := handler.LayerLeveler(LayerBase); !ok || leveler != slog.LevelInfo {
+		t.Fatalf("LayerLeveler(LayerBase) = (%v, %v), want (%v, true)", leveler, ok, slog.LevelInfo)
+	}
+	if layers := handler.Layers(); len(layers) != 1 {
+		t.Fatalf("Layers = %v, want only the base layer", layers)
+	}
+
+	handler.ClearLevel()
+	if handler.HasOverride() {
+		t.Fatal("HasOverride should be false once all layers are cleared")
+	}
+
+	assertHandler.AssertMessage("debug from emergency")
+	assertHandler.AssertMessage("error from remote")
+	assertHandler.AssertMessage("info from base")
+}
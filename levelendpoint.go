@@ -0,0 +1,80 @@
+package slogleveloverride
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// LevelEndpoint is an [http.Handler] following the convention of Prometheus
+// components for changing the log level, so that automation built for them
+// can manage services using this package:
+//
+//	GET  returns the effective level, e.g. "info"
+//	PUT  sets the level given as body, one of debug, info, warn and error
+//
+// GET responses carry what determined the level in the X-Level-Origin
+// header, as formatted by [Origin.String]. Changes are recorded with
+// [SourceHTTP] as source.
+//
+// Mount it on the conventional path, e.g. mux.Handle("/-/loglevel", e).
+// Changes are subject to the policy set with [SetPolicy].
+type LevelEndpoint struct {
+	// Handler is the handler whose level is managed.
+	Handler *OverrideHandler
+}
+
+// endpointLevels are the levels accepted by [LevelEndpoint].
+var endpointLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// ServeHTTP serves GET and PUT requests for the level of e.Handler.
+func (e *LevelEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		_, origin := e.Handler.EffectiveLevel()
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Level-Origin", origin.String())
+		io.WriteString(w, endpointLevelName(e.Handler.effectiveLevel(r.Context()))+"\n")
+	case http.MethodPut:
+		body, err := io.ReadAll(io.LimitReader(r.Body, 64))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		text := strings.ToLower(strings.TrimSpace(string(body)))
+		level, ok := endpointLevels[text]
+		if !ok {
+			http.Error(w, "unknown level "+text+", expected one of debug, info, warn, error", http.StatusBadRequest)
+			return
+		}
+		if err := e.Handler.setLevel("", SourceHTTP, level); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// endpointLevelName returns the name of the nearest level accepted by
+// [LevelEndpoint] at or below level.
+func endpointLevelName(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "error"
+	case level >= slog.LevelWarn:
+		return "warn"
+	case level >= slog.LevelInfo:
+		return "info"
+	default:
+		return "debug"
+	}
+}
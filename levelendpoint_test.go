@@ -0,0 +1,48 @@
+package slogleveloverride
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestLevelEndpoint verifies that the level is read and set with plain text bodies
+func TestLevelEndpoint(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelWarn+1)
+	server := httptest.NewServer(&LevelEndpoint{Handler: handler})
+	defer server.Close()
+
+	do := func(method, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		text, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(text)
+	}
+
+	if code, body := do(http.MethodGet, ""); code != http.StatusOK || body != "warn\n" {
+		t.Errorf("GET returned %d %q, want warn", code, body)
+	}
+	if code, _ := do(http.MethodPut, "DEBUG\n"); code != http.StatusNoContent {
+		t.Errorf("PUT returned %d", code)
+	}
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Errorf("level = %v, want %v", level, slog.LevelDebug)
+	}
+	if code, _ := do(http.MethodPut, "trace"); code != http.StatusBadRequest {
+		t.Errorf("PUT of an unknown level returned %d", code)
+	}
+	if code, _ := do(http.MethodPost, "info"); code != http.StatusMethodNotAllowed {
+		t.Errorf("POST returned %d", code)
+	}
+}
@@ -0,0 +1,90 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Custom levels with names registered by default, see [RegisterLevelName].
+const (
+	LevelTrace  slog.Level = slog.LevelDebug - 4
+	LevelNotice slog.Level = slog.LevelInfo + 2
+	LevelFatal  slog.Level = slog.LevelError + 4
+)
+
+var (
+	levelNamesMu sync.RWMutex
+	levelNames   = map[slog.Level]string{
+		LevelTrace:  "TRACE",
+		LevelNotice: "NOTICE",
+		LevelFatal:  "FATAL",
+	}
+)
+
+// RegisterLevelName registers name for a custom level, so that it is
+// rendered by [ReplaceLevelNames] and accepted wherever this package parses
+// levels, such as specs and flags. Names are matched case-insensitively
+// when parsing. [LevelTrace], [LevelNotice] and [LevelFatal] are registered
+// by default.
+func RegisterLevelName(level slog.Level, name string) {
+	levelNamesMu.Lock()
+	defer levelNamesMu.Unlock()
+	levelNames[level] = strings.ToUpper(name)
+}
+
+// LevelName returns the registered name of level, or level.String() if it
+// has none.
+func LevelName(level slog.Level) string {
+	levelNamesMu.RLock()
+	name, ok := levelNames[level]
+	levelNamesMu.RUnlock()
+	if ok {
+		return name
+	}
+	return level.String()
+}
+
+// ReplaceLevelNames is a ReplaceAttr function for [slog.HandlerOptions]
+// rendering levels with a registered name by that name, e.g. "TRACE"
+// rather than "DEBUG-4".
+func ReplaceLevelNames(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 || a.Key != slog.LevelKey {
+		return a
+	}
+	if level, ok := a.Value.Any().(slog.Level); ok {
+		a.Value = slog.StringValue(LevelName(level))
+	}
+	return a
+}
+
+// LevelNameOptions returns a copy of opts whose ReplaceAttr renders level
+// names with [ReplaceLevelNames] before calling the ReplaceAttr of opts, if
+// any. opts may be nil.
+func LevelNameOptions(opts *slog.HandlerOptions) *slog.HandlerOptions {
+	var named slog.HandlerOptions
+	if opts != nil {
+		named = *opts
+	}
+	replace := named.ReplaceAttr
+	named.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		a = ReplaceLevelNames(groups, a)
+		if replace != nil {
+			return replace(groups, a)
+		}
+		return a
+	}
+	return &named
+}
+
+// lookupLevelName returns the level registered under name.
+func lookupLevelName(name string) (slog.Level, bool) {
+	levelNamesMu.RLock()
+	defer levelNamesMu.RUnlock()
+	for level, n := range levelNames {
+		if strings.EqualFold(n, name) {
+			return level, true
+		}
+	}
+	return 0, false
+}
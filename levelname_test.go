@@ -0,0 +1,53 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestReplaceLevelNames verifies that registered levels are rendered by name
+func TestReplaceLevelNames(t *testing.T) {
+	var buf bytes.Buffer
+	opts := LevelNameOptions(&slog.HandlerOptions{
+		Level: LevelTrace,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	logger := slog.New(slog.NewTextHandler(&buf, opts))
+
+	logger.Log(t.Context(), LevelTrace, "trace")
+	logger.Log(t.Context(), LevelNotice, "notice")
+	logger.Log(t.Context(), slog.LevelWarn+1, "unnamed")
+
+	want := "level=TRACE msg=trace\nlevel=NOTICE msg=notice\nlevel=WARN+1 msg=unnamed\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("output is %q, want %q", got, want)
+	}
+}
+
+// TestRegisterLevelName verifies that registered names are accepted when parsing levels
+func TestRegisterLevelName(t *testing.T) {
+	RegisterLevelName(slog.LevelError+8, "panic")
+	defer func() {
+		levelNamesMu.Lock()
+		delete(levelNames, slog.LevelError+8)
+		levelNamesMu.Unlock()
+	}()
+
+	spec, err := ParseSpec("db=trace,http=Panic")
+	if err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+	if want := (Spec{{Scope: "db", Level: LevelTrace}, {Scope: "http", Level: slog.LevelError + 8}}); spec.String() != want.String() {
+		t.Fatalf("ParseSpec returned %v, want %v", spec, want)
+	}
+	if got := LevelName(slog.LevelError + 8); !strings.EqualFold(got, "panic") {
+		t.Fatalf("LevelName returned %q, want %q", got, "PANIC")
+	}
+}
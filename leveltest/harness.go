@@ -0,0 +1,209 @@
+package leveltest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	slogleveloverride "github.com/martin-viggiano/slog-level-override"
+)
+
+// Clock is a fake clock that only moves when advanced. It times the records
+// and scripted changes of a [Harness]; the handler under test does not read
+// it.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock creates a [Clock] reading start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the current time of c.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// set moves c to t.
+func (c *Clock) set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Entry is a record logged through a [Harness].
+type Entry struct {
+	// At is the time of the record, relative to the start of the harness.
+	At time.Duration
+	// Level and Message are those of the record.
+	Level   slog.Level
+	Message string
+	// Emitted reports whether the record reached the output, i.e. the
+	// handler was enabled for it and did not drop it in Handle.
+	Emitted bool
+}
+
+// String formats e as "+5s WARN message", with a "(suppressed)" suffix
+// for suppressed records.
+func (e Entry) String() string {
+	s := fmt.Sprintf("+%v %v %s", e.At, e.Level, e.Message)
+	if !e.Emitted {
+		s += " (suppressed)"
+	}
+	return s
+}
+
+// Harness runs scripted level changes against an
+// [slogleveloverride.OverrideHandler], ordered by a fake clock, and captures
+// the records logged in between, so that scenarios such as "when the level
+// changes at T+5s, these records appear" can be tested deterministically.
+//
+// Changes scripted with [Harness.At] are applied by [Harness.Advance], in
+// order of time, with the clock set to their time. Records logged with
+// [Harness.Log] are timestamped with the clock. The clock does not drive
+// the handler itself: time-based features such as schedules, dwell,
+// throughput windows, timed restores and the windows of
+// [slogleveloverride.OverrideHandler.DebugAfterPanic] run on the real
+// clock; script their effect with At instead.
+type Harness struct {
+	// Clock is the fake clock of the harness.
+	Clock *Clock
+	// Handler is the handler under test.
+	Handler *slogleveloverride.OverrideHandler
+
+	t        testing.TB
+	start    time.Time
+	output   *bytes.Buffer
+	recorder *Recorder
+	steps    []step
+	entries  []Entry
+}
+
+// step is a scripted change.
+type step struct {
+	at     time.Duration
+	change func(*slogleveloverride.OverrideHandler)
+}
+
+// NewHarness creates a [Harness] for a handler created with
+// [slogleveloverride.NewWithLevel] with level and opts, writing text output
+// with times relative to the start of the harness.
+func NewHarness(t testing.TB, level slog.Leveler, opts ...slogleveloverride.Option) *Harness {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	output := &bytes.Buffer{}
+	text := slog.NewTextHandler(output, &slog.HandlerOptions{
+		Level: slog.Level(-1 << 20),
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.String(slog.TimeKey, "+"+a.Value.Time().Sub(start).String())
+			}
+			return a
+		},
+	})
+	recorder := NewRecorder(text)
+	handler := slogleveloverride.NewWithLevel(recorder, level, opts...)
+	return &Harness{
+		Clock:    NewClock(start),
+		Handler:  handler,
+		t:        t,
+		start:    start,
+		output:   output,
+		recorder: recorder,
+	}
+}
+
+// At scripts change to be applied to the handler once the clock reaches
+// offset from the start of the harness.
+func (h *Harness) At(offset time.Duration, change func(*slogleveloverride.OverrideHandler)) {
+	i, _ := slices.BinarySearchFunc(h.steps, offset, func(s step, at time.Duration) int {
+		if s.at <= at {
+			return -1
+		}
+		return 1
+	})
+	h.steps = slices.Insert(h.steps, i, step{at: offset, change: change})
+}
+
+// SetLevelAt scripts setting the level of the handler at offset.
+func (h *Harness) SetLevelAt(offset time.Duration, level slog.Leveler) {
+	h.At(offset, func(oh *slogleveloverride.OverrideHandler) { oh.SetLevel(level) })
+}
+
+// Advance moves the clock forward by d, applying the changes scripted up
+// to the new time.
+func (h *Harness) Advance(d time.Duration) {
+	h.t.Helper()
+	if d < 0 {
+		h.t.Fatalf("leveltest: cannot advance the clock by %v", d)
+	}
+	end := h.Clock.Now().Add(d)
+	for len(h.steps) > 0 && !h.start.Add(h.steps[0].at).After(end) {
+		s := h.steps[0]
+		h.steps = h.steps[1:]
+		if at := h.start.Add(s.at); at.After(h.Clock.Now()) {
+			h.Clock.set(at)
+		}
+		s.change(h.Handler)
+	}
+	h.Clock.set(end)
+}
+
+// Log logs a record at the current time of the clock, with args as for
+// [slog.Logger.Log]. Like a [slog.Logger], it only passes the record to the
+// handler if the handler is enabled for its level. The record counts as
+// emitted if it then reached the output.
+func (h *Harness) Log(level slog.Level, msg string, args ...any) {
+	h.t.Helper()
+	ctx := context.Background()
+	now := h.Clock.Now()
+	entry := Entry{At: now.Sub(h.start), Level: level, Message: msg}
+	if h.Handler.Enabled(ctx, level) {
+		before := len(h.recorder.Emitted())
+		record := slog.NewRecord(now, level, msg, 0)
+		record.Add(args...)
+		if err := h.Handler.Handle(ctx, record); err != nil {
+			h.t.Errorf("leveltest: handling %q: %v", msg, err)
+		}
+		// The handler may emit records of its own, such as digests, along
+		// with this one.
+		entry.Emitted = slices.ContainsFunc(h.recorder.Emitted()[before:], func(r slog.Record) bool {
+			return r.Message == msg && r.Time.Equal(now)
+		})
+	}
+	h.entries = append(h.entries, entry)
+}
+
+// Entries returns the records logged so far, in order.
+func (h *Harness) Entries() []Entry {
+	return slices.Clone(h.entries)
+}
+
+// Output returns the text output of the handler so far, with the time of
+// each record relative to the start of the harness, e.g. "time=+5s".
+func (h *Harness) Output() string {
+	return h.output.String()
+}
+
+// AssertEmitted fails the test unless the messages of the records that
+// reached the output so far are want, in order.
+func (h *Harness) AssertEmitted(want ...string) {
+	h.t.Helper()
+	var got []string
+	for _, e := range h.entries {
+		if e.Emitted {
+			got = append(got, e.Message)
+		}
+	}
+	if !slices.Equal(got, want) {
+		h.t.Errorf("emitted %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package leveltest
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	slogleveloverride "github.com/martin-viggiano/slog-level-override"
+)
+
+// TestHarness verifies that scripted level changes apply at their time
+func TestHarness(t *testing.T) {
+	h := NewHarness(t, slog.LevelInfo)
+	h.SetLevelAt(5*time.Second, slog.LevelDebug)
+	h.At(10*time.Second, (*slogleveloverride.OverrideHandler).ClearLevel)
+	h.SetLevelAt(8*time.Second, slog.LevelWarn)
+
+	h.Log(slog.LevelDebug, "before")
+	h.Advance(5 * time.Second)
+	h.Log(slog.LevelDebug, "during")
+	h.Advance(4 * time.Second)
+	h.Log(slog.LevelInfo, "raised")
+	h.Log(slog.LevelWarn, "warn")
+	h.Advance(time.Hour)
+	h.Log(slog.LevelInfo, "cleared")
+
+	h.AssertEmitted("during", "warn", "cleared")
+	var got []string
+	for _, e := range h.Entries() {
+		got = append(got, e.String())
+	}
+	want := []string{
+		"+0s DEBUG before (suppressed)",
+		"+5s DEBUG during",
+		"+9s INFO raised (suppressed)",
+		"+9s WARN warn",
+		"+1h0m9s INFO cleared",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Entries = %q, want %q", got, want)
+	}
+	if !strings.Contains(h.Output(), "time=+5s level=DEBUG msg=during") {
+		t.Errorf("Output = %q", h.Output())
+	}
+}
+
+// TestHarnessDroppedInHandle verifies that records dropped in Handle are not reported as emitted
+func TestHarnessDroppedInHandle(t *testing.T) {
+	h := NewHarness(t, slog.LevelInfo, slogleveloverride.WithAttrRateLimit("host", 1))
+
+	h.Log(slog.LevelInfo, "first", "host", "a")
+	h.Log(slog.LevelInfo, "limited", "host", "a")
+	h.Log(slog.LevelInfo, "other host", "host", "b")
+
+	h.AssertEmitted("first", "other host")
+	if strings.Contains(h.Output(), "limited") {
+		t.Errorf("Output = %q", h.Output())
+	}
+}
@@ -0,0 +1,179 @@
+// Package leveltest provides utilities for testing code that relies on
+// dynamic log levels set through slogleveloverride.
+package leveltest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"testing/slogtest"
+
+	slogleveloverride "github.com/martin-viggiano/slog-level-override"
+)
+
+// Recorder is an [slog.Handler] that wraps another handler and records
+// every record it sees, split by whether the wrapped handler was enabled for
+// it.
+//
+// Recorder reports itself as enabled for every level, so that records the
+// wrapped handler would suppress still reach Handle and can be inspected.
+// Emitted records are forwarded to the wrapped handler, suppressed ones are
+// not.
+type Recorder struct {
+	next      slog.Handler
+	recording *recording
+}
+
+type recording struct {
+	mu         sync.Mutex
+	emitted    []slog.Record
+	suppressed []slog.Record
+}
+
+var _ slog.Handler = (*Recorder)(nil)
+
+// NewRecorder creates a [Recorder] wrapping h.
+func NewRecorder(h slog.Handler) *Recorder {
+	return &Recorder{
+		next:      h,
+		recording: &recording{},
+	}
+}
+
+// Enabled always returns true.
+func (r *Recorder) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle records the record and forwards it to the wrapped handler if that
+// handler is enabled for its level.
+func (r *Recorder) Handle(ctx context.Context, record slog.Record) error {
+	enabled := r.next.Enabled(ctx, record.Level)
+
+	r.recording.mu.Lock()
+	if enabled {
+		r.recording.emitted = append(r.recording.emitted, record.Clone())
+	} else {
+		r.recording.suppressed = append(r.recording.suppressed, record.Clone())
+	}
+	r.recording.mu.Unlock()
+
+	if !enabled {
+		return nil
+	}
+	return r.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a [Recorder] sharing the recorded records of r and
+// wrapping the wrapped handler with attrs added.
+func (r *Recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Recorder{next: r.next.WithAttrs(attrs), recording: r.recording}
+}
+
+// WithGroup returns a [Recorder] sharing the recorded records of r and
+// wrapping the wrapped handler with the group added.
+func (r *Recorder) WithGroup(name string) slog.Handler {
+	return &Recorder{next: r.next.WithGroup(name), recording: r.recording}
+}
+
+// Unwrap returns the wrapped handler.
+func (r *Recorder) Unwrap() slog.Handler {
+	return r.next
+}
+
+// Emitted returns the records forwarded to the wrapped handler so far.
+func (r *Recorder) Emitted() []slog.Record {
+	r.recording.mu.Lock()
+	defer r.recording.mu.Unlock()
+	return slices.Clone(r.recording.emitted)
+}
+
+// Suppressed returns the records the wrapped handler was not enabled for.
+func (r *Recorder) Suppressed() []slog.Record {
+	r.recording.mu.Lock()
+	defer r.recording.mu.Unlock()
+	return slices.Clone(r.recording.suppressed)
+}
+
+// Reset discards all recorded records.
+func (r *Recorder) Reset() {
+	r.recording.mu.Lock()
+	defer r.recording.mu.Unlock()
+	r.recording.emitted = nil
+	r.recording.suppressed = nil
+}
+
+// Messages returns the messages of records.
+func Messages(records []slog.Record) []string {
+	msgs := make([]string, len(records))
+	for i, r := range records {
+		msgs[i] = r.Message
+	}
+	return msgs
+}
+
+// AssertThreshold fails the test unless h is enabled for level want and
+// disabled for the level just below it.
+func AssertThreshold(t testing.TB, h slog.Handler, want slog.Level) {
+	t.Helper()
+	ctx := context.Background()
+	if !h.Enabled(ctx, want) {
+		t.Errorf("handler is not enabled for %v", want)
+	}
+	if h.Enabled(ctx, want-1) {
+		t.Errorf("handler is enabled for %v, below the expected threshold %v", want-1, want)
+	}
+}
+
+// AssertTransition fails the test unless the threshold of h is from before
+// change is called and to afterwards.
+func AssertTransition(t testing.TB, h slog.Handler, from, to slog.Level, change func()) {
+	t.Helper()
+	AssertThreshold(t, h, from)
+	change()
+	AssertThreshold(t, h, to)
+}
+
+// ScopedLevel sets the level override of the [slogleveloverride.OverrideHandler]
+// found in the chain of h, and restores the previous override, or its
+// absence, when the test and its subtests complete.
+func ScopedLevel(t testing.TB, h slog.Handler, level slog.Leveler) {
+	t.Helper()
+	oh := slogleveloverride.FindOverrideHandler(h)
+	if oh == nil {
+		t.Fatalf("no OverrideHandler found in %T", h)
+	}
+	previous, ok := oh.LayerLeveler(slogleveloverride.LayerBase)
+	oh.SetLevel(level)
+	t.Cleanup(func() {
+		if ok {
+			oh.SetLevel(previous)
+		} else {
+			oh.ClearLevel()
+		}
+	})
+}
+
+// Conformance runs the [slogtest] conformance suite against the handler
+// returned by wrap, which typically composes an
+// [slogleveloverride.OverrideHandler] and further middleware around the
+// given base handler. The base handler is a [slog.JSONHandler] whose output
+// is parsed to check the results, so wrap must forward records to it.
+func Conformance(t *testing.T, wrap func(base slog.Handler) slog.Handler) {
+	t.Helper()
+	var buf bytes.Buffer
+	slogtest.Run(t, func(*testing.T) slog.Handler {
+		buf.Reset()
+		return wrap(slog.NewJSONHandler(&buf, nil))
+	}, func(t *testing.T) map[string]any {
+		var m map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatalf("parsing output %q: %v", buf.String(), err)
+		}
+		return m
+	})
+}
@@ -0,0 +1,83 @@
+package leveltest
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+
+	slogleveloverride "github.com/martin-viggiano/slog-level-override"
+)
+
+// TestRecorder verifies that emitted and suppressed records are recorded separately
+func TestRecorder(t *testing.T) {
+	handler := slogleveloverride.NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	recorder := NewRecorder(handler)
+	logger := slog.New(recorder).With("component", "test")
+
+	logger.Debug("debug message")
+	logger.Warn("warn message")
+
+	if got, want := Messages(recorder.Emitted()), []string{"warn message"}; !slices.Equal(got, want) {
+		t.Errorf("Emitted = %v, want %v", got, want)
+	}
+	if got, want := Messages(recorder.Suppressed()), []string{"debug message"}; !slices.Equal(got, want) {
+		t.Errorf("Suppressed = %v, want %v", got, want)
+	}
+
+	recorder.Reset()
+	if len(recorder.Emitted())+len(recorder.Suppressed()) != 0 {
+		t.Error("Reset should discard all records")
+	}
+}
+
+// TestScopedLevel verifies that the previous override is restored on cleanup
+func TestScopedLevel(t *testing.T) {
+	handler := slogleveloverride.NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	unset := slogleveloverride.New(slog.DiscardHandler)
+
+	t.Run("scoped", func(t *testing.T) {
+		ScopedLevel(t, NewRecorder(handler), slog.LevelDebug)
+		ScopedLevel(t, unset, slog.LevelError)
+		AssertThreshold(t, handler, slog.LevelDebug)
+		AssertThreshold(t, unset, slog.LevelError)
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		throttled := slogleveloverride.Once(slog.New(handler), "key")
+		ScopedLevel(t, throttled.Handler(), slog.LevelError)
+		AssertThreshold(t, handler, slog.LevelError)
+	})
+
+	AssertThreshold(t, handler, slog.LevelWarn)
+	if unset.HasOverride() {
+		t.Error("override should have been cleared")
+	}
+}
+
+// TestAssertTransition verifies the transition helper
+func TestAssertTransition(t *testing.T) {
+	handler := slogleveloverride.NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	AssertTransition(t, handler, slog.LevelInfo, slog.LevelError, func() {
+		handler.SetLevel(slog.LevelError)
+	})
+}
+
+// wrappingHandler is middleware forwarding every call to the handler it wraps
+type wrappingHandler struct {
+	slog.Handler
+}
+
+func (h wrappingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return wrappingHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h wrappingHandler) WithGroup(name string) slog.Handler {
+	return wrappingHandler{h.Handler.WithGroup(name)}
+}
+
+// TestConformance verifies the conformance of an OverrideHandler wrapped in middleware
+func TestConformance(t *testing.T) {
+	Conformance(t, func(base slog.Handler) slog.Handler {
+		return wrappingHandler{slogleveloverride.NewWithLevel(base, slog.LevelDebug)}
+	})
+}
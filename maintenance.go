@@ -0,0 +1,119 @@
+package slogleveloverride
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// LayerMaintenance is the layer of maintenance windows booked with
+// [OverrideHandler.ScheduleOverride]. It is above [LayerBase], so a booked
+// window takes precedence over manual SetLevel calls while it lasts.
+const LayerMaintenance Layer = 50
+
+// maintenanceWindows holds the active windows of a handler family.
+type maintenanceWindows struct {
+	mu     sync.Mutex
+	active map[*ScheduledOverride]struct{}
+}
+
+// ScheduledOverride is a maintenance window booked with
+// [OverrideHandler.ScheduleOverride].
+type ScheduledOverride struct {
+	h     *OverrideHandler
+	level slog.Level
+
+	mu       sync.Mutex
+	start    *time.Timer
+	end      *time.Timer
+	active   bool
+	finished bool
+}
+
+// ScheduleOverride books a maintenance window: from start until end, the
+// override of [LayerMaintenance] is level, after which it reverts
+// automatically. A start in the past begins the window right away. While
+// windows overlap, the most verbose level applies.
+//
+// An error is returned if end is not after start or has already passed, or
+// if the level is rejected by the policy set with [SetPolicy].
+func (h *OverrideHandler) ScheduleOverride(start, end time.Time, level slog.Level) (*ScheduledOverride, error) {
+	if !end.After(start) {
+		return nil, errors.New("slogleveloverride: maintenance window ends before it starts")
+	}
+	if !end.After(time.Now()) {
+		return nil, errors.New("slogleveloverride: maintenance window has already ended")
+	}
+	if err := checkPolicy("", level); err != nil {
+		return nil, err
+	}
+	o := &ScheduledOverride{h: h, level: level}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.start = time.AfterFunc(time.Until(start), o.begin)
+	o.end = time.AfterFunc(time.Until(end), o.Cancel)
+	return o, nil
+}
+
+// begin starts the window.
+func (o *ScheduledOverride) begin() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.finished {
+		return
+	}
+	o.active = true
+	o.h.state.maintenance.update(o.h, o, true)
+}
+
+// Cancel ends the window, or cancels it if it has not started yet. It is
+// called automatically at the end of the window.
+func (o *ScheduledOverride) Cancel() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.finished {
+		return
+	}
+	o.finished = true
+	o.start.Stop()
+	o.end.Stop()
+	if o.active {
+		o.active = false
+		o.h.state.maintenance.update(o.h, o, false)
+	}
+}
+
+// Active reports whether the window is in progress.
+func (o *ScheduledOverride) Active() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.active
+}
+
+// update adds or removes o from the active windows and sets the override of
+// [LayerMaintenance] to the most verbose level among them.
+func (w *maintenanceWindows) update(h *OverrideHandler, o *ScheduledOverride, active bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if active {
+		if w.active == nil {
+			w.active = make(map[*ScheduledOverride]struct{})
+		}
+		w.active[o] = struct{}{}
+	} else {
+		delete(w.active, o)
+	}
+	if len(w.active) == 0 {
+		h.state.clearLayer(LayerMaintenance)
+		return
+	}
+	var level slog.Level
+	first := true
+	for a := range w.active {
+		if first || a.level < level {
+			level, first = a.level, false
+		}
+	}
+	h.state.setLayer(LayerMaintenance, "maintenance", level)
+}
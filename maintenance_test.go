@@ -0,0 +1,86 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or a deadline passes
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestScheduleOverride verifies that a window applies while active and reverts afterwards
+func TestScheduleOverride(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	now := time.Now()
+
+	if _, err := handler.ScheduleOverride(now, now.Add(-time.Second), slog.LevelDebug); err == nil {
+		t.Fatal("ScheduleOverride should fail for a window ending before it starts")
+	}
+
+	window, err := handler.ScheduleOverride(now, now.Add(200*time.Millisecond), slog.LevelDebug)
+	if err != nil {
+		t.Fatalf("ScheduleOverride failed: %v", err)
+	}
+	waitFor(t, "window start", window.Active)
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Fatalf("level is %v during the window, want %v", level, slog.LevelDebug)
+	}
+
+	// A manual change does not end the window
+	handler.SetLevel(slog.LevelError)
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Fatalf("level is %v after SetLevel during the window, want %v", level, slog.LevelDebug)
+	}
+
+	waitFor(t, "window end", func() bool { return !window.Active() })
+	if level, _ := handler.Level(); level != slog.LevelError {
+		t.Fatalf("level is %v after the window, want %v", level, slog.LevelError)
+	}
+}
+
+// TestScheduleOverrideCancel verifies cancellation and overlapping windows
+func TestScheduleOverrideCancel(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	now := time.Now()
+
+	future, err := handler.ScheduleOverride(now.Add(time.Hour), now.Add(2*time.Hour), slog.LevelDebug)
+	if err != nil {
+		t.Fatalf("ScheduleOverride failed: %v", err)
+	}
+	future.Cancel()
+
+	info, err := handler.ScheduleOverride(now, now.Add(time.Hour), slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("ScheduleOverride failed: %v", err)
+	}
+	debug, err := handler.ScheduleOverride(now, now.Add(time.Hour), slog.LevelDebug)
+	if err != nil {
+		t.Fatalf("ScheduleOverride failed: %v", err)
+	}
+	waitFor(t, "windows start", func() bool { return info.Active() && debug.Active() })
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Fatalf("level is %v with overlapping windows, want %v", level, slog.LevelDebug)
+	}
+
+	debug.Cancel()
+	if level, _ := handler.Level(); level != slog.LevelInfo {
+		t.Fatalf("level is %v after cancelling the debug window, want %v", level, slog.LevelInfo)
+	}
+	info.Cancel()
+	if level, _ := handler.Level(); level != slog.LevelWarn {
+		t.Fatalf("level is %v after cancelling all windows, want %v", level, slog.LevelWarn)
+	}
+	if future.Active() {
+		t.Fatal("cancelled window should not be active")
+	}
+}
@@ -0,0 +1,127 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// recordOverhead is the estimated size of a buffered record besides its
+// message and attributes.
+const recordOverhead = 128
+
+// MemoryBudget caps the memory held by the buffering features of handlers,
+// [WithSuppressedBuffer], [WithTraceBuffer], [WithKeyedBuffer] and
+// [WithSuppressedDigest], so that enabling them cannot exhaust the memory
+// of a service. A budget can be shared by several handlers.
+//
+// When an entry does not fit, the feature adding it first evicts its own
+// oldest entries; if that is not enough, because other features hold the
+// budget, the entry is rejected. Sizes are estimates of the memory held by
+// the entries, not exact accounting.
+type MemoryBudget struct {
+	max int64
+
+	used     atomic.Int64
+	evicted  atomic.Uint64
+	rejected atomic.Uint64
+}
+
+// MemoryStatus is the usage of a [MemoryBudget].
+type MemoryStatus struct {
+	// Max is the cap of the budget, in bytes.
+	Max int64 `json:"max"`
+	// Used is the estimated memory held by the entries, in bytes.
+	Used int64 `json:"used"`
+	// Evicted is the number of entries evicted to make room for others.
+	Evicted uint64 `json:"evicted"`
+	// Rejected is the number of entries rejected for lack of room.
+	Rejected uint64 `json:"rejected"`
+}
+
+// NewMemoryBudget returns a MemoryBudget of maxBytes bytes.
+func NewMemoryBudget(maxBytes int64) *MemoryBudget {
+	return &MemoryBudget{max: maxBytes}
+}
+
+// WithMemoryBudget accounts the memory held by the buffering features of
+// the handler against budget.
+func WithMemoryBudget(budget *MemoryBudget) Option {
+	return func(o *options) {
+		o.memory = budget
+	}
+}
+
+// Status returns the current usage of b.
+func (b *MemoryBudget) Status() MemoryStatus {
+	return MemoryStatus{
+		Max:      b.max,
+		Used:     b.used.Load(),
+		Evicted:  b.evicted.Load(),
+		Rejected: b.rejected.Load(),
+	}
+}
+
+// reserve accounts for n more bytes if they fit in b. A nil budget has
+// room for everything.
+func (b *MemoryBudget) reserve(n int64) bool {
+	if b == nil {
+		return true
+	}
+	for {
+		used := b.used.Load()
+		if used+n > b.max {
+			return false
+		}
+		if b.used.CompareAndSwap(used, used+n) {
+			return true
+		}
+	}
+}
+
+// release returns n bytes to b.
+func (b *MemoryBudget) release(n int64) {
+	if b != nil {
+		b.used.Add(-n)
+	}
+}
+
+// evict releases the n bytes of an evicted entry.
+func (b *MemoryBudget) evict(n int64) {
+	if b != nil {
+		b.used.Add(-n)
+		b.evicted.Add(1)
+	}
+}
+
+// reject accounts for a rejected entry.
+func (b *MemoryBudget) reject() {
+	if b != nil {
+		b.rejected.Add(1)
+	}
+}
+
+// recordSize estimates the memory held by a clone of record.
+func recordSize(record slog.Record) int64 {
+	n := recordOverhead + len(record.Message)
+	record.Attrs(func(a slog.Attr) bool {
+		n += attrSize(a)
+		return true
+	})
+	return int64(n)
+}
+
+// attrSize estimates the memory held by a.
+func attrSize(a slog.Attr) int {
+	n := len(a.Key) + 16
+	switch a.Value.Kind() {
+	case slog.KindString:
+		n += len(a.Value.String())
+	case slog.KindGroup:
+		for _, ga := range a.Value.Group() {
+			n += attrSize(ga)
+		}
+	case slog.KindAny, slog.KindLogValuer:
+		n += 32
+	}
+	return n
+}
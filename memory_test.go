@@ -0,0 +1,77 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMemoryBudgetSuppressed verifies that the suppressed buffer evicts its oldest records to stay within the budget
+func TestMemoryBudgetSuppressed(t *testing.T) {
+	budget := NewMemoryBudget(200)
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelInfo, WithSuppressedBuffer(0, 0), WithMemoryBudget(budget))
+	logger := slog.New(handler)
+	for i := range 10 {
+		logger.Debug("a debug record of some length", "n", i)
+	}
+
+	status := budget.Status()
+	if status.Used == 0 || status.Used > 200 {
+		t.Fatalf("Used = %d, want between 1 and 200", status.Used)
+	}
+	if status.Evicted == 0 {
+		t.Error("Evicted = 0, want evictions")
+	}
+	var dump bytes.Buffer
+	if err := handler.DumpSuppressed(&dump); err != nil {
+		t.Fatalf("DumpSuppressed failed: %v", err)
+	}
+	if int64(dump.Len()) != status.Used || !strings.Contains(dump.String(), "n=9") {
+		t.Errorf("dumped %q, want the most recent %d bytes", dump.String(), status.Used)
+	}
+}
+
+// TestMemoryBudgetShared verifies that a budget held by one feature rejects the entries of another
+func TestMemoryBudgetShared(t *testing.T) {
+	budget := NewMemoryBudget(400)
+	digest := NewWithLevel(slog.DiscardHandler, slog.LevelInfo, WithSuppressedDigest(time.Hour, 3), WithMemoryBudget(budget))
+	buffer := NewWithLevel(slog.DiscardHandler, slog.LevelInfo, WithKeyedBuffer("job_id", 8, 8, 0), WithMemoryBudget(budget))
+
+	for i := range 10 {
+		slog.New(digest).Debug(strings.Repeat("m", i+1))
+	}
+	held := budget.Status().Used
+	slog.New(buffer).Debug("buffered", "job_id", 1)
+	status := budget.Status()
+	if status.Used != held || status.Rejected == 0 {
+		t.Errorf("status = %+v, want the buffered record rejected with %d bytes used", status, held)
+	}
+
+	buffer.EndKey("1")
+	digest.opts.digest.take(time.Now().Add(2 * time.Hour))
+	if used := budget.Status().Used; used != 0 {
+		t.Errorf("Used = %d after releasing everything, want 0", used)
+	}
+}
+
+// TestMemoryBudgetKeyedBuffer verifies that keyed buffers release their memory when flushed
+func TestMemoryBudgetKeyedBuffer(t *testing.T) {
+	budget := NewMemoryBudget(1 << 20)
+	var out bytes.Buffer
+	handler := NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo}, WithKeyedBuffer("job_id", 8, 8, 0), WithMemoryBudget(budget))
+	logger := slog.New(handler).With("job_id", 7)
+
+	logger.Debug("buffered")
+	if budget.Status().Used == 0 {
+		t.Fatal("Used = 0 with a buffered record")
+	}
+	logger.Error("failed")
+	if used := budget.Status().Used; used != 0 {
+		t.Errorf("Used = %d after the flush, want 0", used)
+	}
+	if !strings.Contains(out.String(), "buffered") {
+		t.Errorf("output %q lacks the buffered record", out.String())
+	}
+}
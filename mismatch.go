@@ -0,0 +1,97 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// MismatchMessage is the message of the warning records sent by
+// [WithMismatchCheck] when no report function is given.
+const MismatchMessage = "level override is more verbose than the wrapped handler"
+
+// Mismatch describes an override more verbose than the underlying handler:
+// records between Override and Wrapped pass the override but are still
+// dropped by the underlying handler.
+type Mismatch struct {
+	// Override is the level of the override in effect.
+	Override slog.Level
+	// Wrapped is the lowest level the underlying handler is enabled for.
+	Wrapped slog.Level
+}
+
+// mismatchCheck holds the configuration of WithMismatchCheck.
+type mismatchCheck struct {
+	interval time.Duration
+	report   func(Mismatch)
+
+	last atomic.Int64 // Unix nanoseconds of the last periodic check
+}
+
+// WithMismatchCheck detects overrides more verbose than the underlying
+// handler's own level, meaning records will still be dropped.
+//
+// The check runs whenever the override of the handler changes and, if
+// interval is positive, on the first handler call after interval has
+// elapsed, which catches dynamic levelers and underlying handlers whose
+// level changes. Mismatches are passed to report, or sent as a Warn record
+// with message [MismatchMessage] to the underlying handler if report is nil.
+func WithMismatchCheck(interval time.Duration, report func(Mismatch)) Option {
+	return func(o *options) {
+		c := &mismatchCheck{interval: interval, report: report}
+		c.last.Store(time.Now().UnixNano())
+		o.mismatch = c
+	}
+}
+
+// CheckMismatch reports whether the override in effect, including the
+// global one, is more verbose than the underlying handler. It returns false
+// if no override is set.
+func (h *OverrideHandler) CheckMismatch(ctx context.Context) (Mismatch, bool) {
+	leveler, ok := globalState.load()
+	if !ok {
+		leveler, ok = h.state.load()
+	}
+	if !ok {
+		return Mismatch{}, false
+	}
+	m := Mismatch{Override: leveler.Level(), Wrapped: handlerLevel(ctx, h.wrapped())}
+	return m, m.Override < m.Wrapped
+}
+
+// checkMismatch runs the check of WithMismatchCheck and reports a mismatch.
+func (h *OverrideHandler) checkMismatch(ctx context.Context) {
+	if h.opts.mismatch == nil {
+		return
+	}
+	m, ok := h.CheckMismatch(ctx)
+	if !ok {
+		return
+	}
+	if h.opts.mismatch.report != nil {
+		h.opts.mismatch.report(m)
+		return
+	}
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, MismatchMessage, 0)
+	r.AddAttrs(
+		slog.String("override", m.Override.String()),
+		slog.String("wrapped_level", m.Wrapped.String()),
+	)
+	_ = h.wrapped().Handle(ctx, r)
+}
+
+// checkMismatchPeriodically runs checkMismatch if the interval of
+// WithMismatchCheck has elapsed since the last periodic check.
+func (h *OverrideHandler) checkMismatchPeriodically(ctx context.Context) {
+	c := h.opts.mismatch
+	if c == nil || c.interval <= 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	last := c.last.Load()
+	if now-last < int64(c.interval) || !c.last.CompareAndSwap(last, now) {
+		return
+	}
+	h.checkMismatch(ctx)
+}
@@ -0,0 +1,62 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestMismatchCheckReport verifies that setting an override below the wrapped level is reported
+func TestMismatchCheckReport(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	var reports []Mismatch
+	handler := New(assertHandler, WithMismatchCheck(0, func(m Mismatch) {
+		reports = append(reports, m)
+	}))
+
+	handler.SetLevel(slog.LevelWarn)
+	handler.SetLayerLevel(LayerRemote, slog.LevelDebug)
+	handler.ClearLayer(LayerRemote)
+
+	want := []Mismatch{{Override: slog.LevelDebug, Wrapped: slog.LevelInfo}}
+	if len(reports) != len(want) || reports[0] != want[0] {
+		t.Fatalf("reports = %v, want %v", reports, want)
+	}
+	if _, ok := handler.CheckMismatch(t.Context()); ok {
+		t.Fatal("CheckMismatch should report no mismatch at Warn")
+	}
+}
+
+// TestMismatchCheckWarning verifies the self-log warning and the periodic check
+func TestMismatchCheckWarning(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelDebug, WithMismatchCheck(time.Hour, nil))
+	assertWarning := func() {
+		t.Helper()
+		assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+			Message: MismatchMessage,
+			Level:   slog.LevelWarn,
+			Attrs: map[string]any{
+				"override":      "DEBUG",
+				"wrapped_level": "INFO",
+			},
+			AllAttrsMatch: true,
+		})
+	}
+	assertWarning()
+
+	logger := slog.New(handler)
+	logger.Info("before interval")
+	assertHandler.AssertMessage("before interval")
+
+	handler.opts.mismatch.last.Add(-int64(time.Hour))
+	logger.Info("after interval")
+	assertWarning()
+	assertHandler.AssertMessage("after interval")
+}
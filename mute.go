@@ -0,0 +1,24 @@
+package slogleveloverride
+
+// Mute suppresses every record regardless of the level overrides, the global
+// level, source rules and the underlying handler, as if the level were
+// raised above any possible level. It is meant as a panic button when a
+// logging loop fills up the disk.
+//
+// Overrides set while muted are kept and take effect on [Unmute], which
+// restores the state in place before Mute. Handlers derived with WithAttrs
+// and WithGroup are muted as well, unless created with [WithIsolatedChildren].
+func (h *OverrideHandler) Mute() {
+	h.opts.slow.Store(true)
+	h.state.muted.Store(true)
+}
+
+// Unmute reverts [OverrideHandler.Mute].
+func (h *OverrideHandler) Unmute() {
+	h.state.muted.Store(false)
+}
+
+// Muted reports whether the handler is muted.
+func (h *OverrideHandler) Muted() bool {
+	return h.state.muted.Load()
+}
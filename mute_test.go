@@ -0,0 +1,41 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestMute verifies that muting suppresses everything and unmuting restores the previous state
+func TestMute(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelDebug)
+	handler.SetLayerLevel(LayerEmergency, slog.LevelDebug)
+	logger := slog.New(handler).With("component", "db")
+
+	handler.Mute()
+	if !handler.Muted() {
+		t.Fatal("Muted should be true after Mute")
+	}
+	logger.Error("dropped while muted")
+	if handler.Enabled(context.Background(), slog.LevelError) {
+		t.Fatal("Enabled should be false while muted")
+	}
+	// Handle drops records even if Enabled is bypassed
+	_ = handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelError, "bypass", 0))
+
+	// Changes while muted take effect on Unmute
+	handler.ClearLayer(LayerEmergency)
+	handler.SetLevel(slog.LevelWarn)
+
+	handler.Unmute()
+	logger.Info("dropped by override")
+	logger.Warn("forwarded after unmute")
+
+	assertHandler.AssertMessage("forwarded after unmute")
+}
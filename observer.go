@@ -0,0 +1,93 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// Observer is notified by a [Registry] of changes to its handlers, so that
+// the level state can be mirrored to external systems.
+//
+// Observers are called synchronously, from the goroutine making the change,
+// and must not call back into the Registry.
+type Observer interface {
+	// OnRegister is called when h is registered under name.
+	OnRegister(name string, h *OverrideHandler)
+	// OnLevelChange is called when the override in effect of the handler
+	// registered under name is set or cleared, however the change is made.
+	// level is nil if the handler has no override left.
+	OnLevelChange(name string, level slog.Leveler)
+	// OnRemove is called when the handler registered under name is
+	// unregistered or replaced.
+	OnRemove(name string)
+}
+
+// AddObserver adds o to the observers of the registry. o.OnRegister is
+// called right away for every handler already registered, in name order.
+func (r *Registry) AddObserver(o Observer) {
+	r.mu.Lock()
+	var observers []Observer
+	if p := r.observers.Load(); p != nil {
+		observers = *p
+	}
+	observers = append(slices.Clip(observers), o)
+	r.observers.Store(&observers)
+	names := slices.Sorted(maps.Keys(r.handlers))
+	handlers := make([]*OverrideHandler, len(names))
+	for i, name := range names {
+		handlers[i] = r.handlers[name]
+	}
+	r.mu.Unlock()
+
+	for i, name := range names {
+		o.OnRegister(name, handlers[i])
+	}
+}
+
+// notifyObservers calls fn for every observer of the registry. It does not
+// lock r.mu, since level changes may be made while it is held.
+func (r *Registry) notifyObservers(fn func(Observer)) {
+	p := r.observers.Load()
+	if p == nil {
+		return
+	}
+	for _, o := range *p {
+		fn(o)
+	}
+}
+
+// observe starts notifying the observers of level changes of h, registered
+// under name, and returns a function to stop.
+func (r *Registry) observe(name string, h *OverrideHandler) func() {
+	return h.state.watch(func(level slog.Leveler) {
+		r.notifyObservers(func(o Observer) {
+			o.OnLevelChange(name, level)
+		})
+	})
+}
+
+// watch calls fn with the resulting override after every change, and
+// returns a function to stop.
+func (s *levelState) watch(fn func(slog.Leveler)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watchers == nil {
+		s.watchers = make(map[uint64]func(slog.Leveler))
+	}
+	id := s.nextWatch
+	s.nextWatch++
+	s.watchers[id] = fn
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.watchers, id)
+	}
+}
+
+// notify calls every watcher with level.
+func notify(watchers []func(slog.Leveler), level slog.Leveler) {
+	for _, w := range watchers {
+		w(level)
+	}
+}
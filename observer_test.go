@@ -0,0 +1,72 @@
+package slogleveloverride
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+// recordingObserver records the notifications it receives.
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) OnRegister(name string, _ *OverrideHandler) {
+	o.events = append(o.events, "register "+name)
+}
+
+func (o *recordingObserver) OnLevelChange(name string, level slog.Leveler) {
+	if level == nil {
+		o.events = append(o.events, "clear "+name)
+		return
+	}
+	o.events = append(o.events, fmt.Sprintf("level %s %v", name, level.Level()))
+}
+
+func (o *recordingObserver) OnRemove(name string) {
+	o.events = append(o.events, "remove "+name)
+}
+
+// TestObserver verifies the notifications of registrations, level changes and removals
+func TestObserver(t *testing.T) {
+	registry := NewRegistry()
+	db := New(slog.DiscardHandler)
+	registry.Register("db", db)
+
+	observer := &recordingObserver{}
+	registry.AddObserver(observer)
+
+	http := New(slog.DiscardHandler)
+	registry.Register("http", http)
+	registry.SetLevel("http", slog.LevelWarn)
+	db.SetLevel(slog.LevelDebug)
+	db.ClearLevel()
+	if err := registry.ApplySpec(Spec{{Scope: "*", Level: slog.LevelError}}); err != nil {
+		t.Fatalf("ApplySpec failed: %v", err)
+	}
+	registry.Register("http", New(slog.DiscardHandler))
+	registry.Unregister("db")
+
+	// Changes of unregistered handlers are not observed
+	db.SetLevel(slog.LevelInfo)
+	http.SetLevel(slog.LevelInfo)
+
+	want := []string{
+		"register db",
+		"register http",
+		"level http WARN",
+		"level db DEBUG",
+		"clear db",
+		"level db ERROR",
+		"level http ERROR",
+		"remove http",
+		"register http",
+		"remove db",
+	}
+	// ApplySpec changes handlers in map order
+	slices.Sort(observer.events[5:7])
+	if !slices.Equal(observer.events, want) {
+		t.Fatalf("events = %q, want %q", observer.events, want)
+	}
+}
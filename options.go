@@ -0,0 +1,158 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Option configures an [OverrideHandler] created with [New] or [NewWithLevel].
+type Option func(*options)
+
+// options holds the configuration of an [OverrideHandler]. It is shared by
+// every handler derived through WithAttrs and WithGroup.
+type options struct {
+	isolatedChildren bool
+	handleFiltering  bool
+	panicRecovery    bool
+	forceAllWarning  bool
+	contextLevels    bool
+	overriddenKey    string
+	thresholdKey     string
+	stackKey         string
+
+	deadlineMargin time.Duration
+	deadlineLevel  slog.Level
+
+	throughput    *throughputBudget
+	rateLimit     *attrRateLimit
+	repeatBackoff *repeatBackoff
+	digest        *suppressedDigest
+	mismatch      *mismatchCheck
+
+	handleErrors *handleErrors
+	errorBackoff *errorBackoff
+	fallback     *fallbackState
+	async        *asyncQueue
+	diskGuard    *diskGuard
+	traceBuffer  *traceBuffer
+	suppressed   *suppressedBuffer
+	allowlist    *debugAllowlist
+	sideSink     slog.Handler
+	memory       *MemoryBudget
+	panicReport  func(any)
+	throttle     throttleCounters
+
+	sourceRules atomic.Pointer[sourceRules]
+	remapRules  atomic.Pointer[[]RemapRule]
+	rules       atomic.Pointer[compiledRules]
+	diagnostics atomic.Bool
+	replaced    atomic.Pointer[replacement]
+
+	// root is the handler passed to New, before any derivation.
+	root slog.Handler
+
+	// slow is set if Enabled must consult anything besides the level
+	// override: options inspecting records, or features such as rules and
+	// muting that were used at least once on the handler family. Otherwise,
+	// Enabled skips straight to the override after checking this flag, the
+	// global level and whether [ForceCtx] was ever used.
+	slow atomic.Bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.suppressed != nil {
+		o.suppressed.budget = o.memory
+	}
+	if o.traceBuffer != nil {
+		o.traceBuffer.budget = o.memory
+	}
+	if o.digest != nil {
+		o.digest.budget = o.memory
+	}
+	o.slow.Store(o.panicRecovery || o.contextLevels || o.deadlineMargin > 0 || o.throughput != nil ||
+		o.errorBackoff != nil || o.async != nil || o.diskGuard != nil || o.allowlist != nil || o.digest != nil ||
+		o.suppressed != nil || o.traceBuffer != nil || o.sideSink != nil)
+	return o
+}
+
+// WithIsolatedChildren makes handlers derived via WithAttrs and WithGroup
+// hold their own level override.
+//
+// A derived handler starts with the level its parent had at derivation time,
+// resolved to a static [slog.Level] if the parent used a dynamic
+// [slog.Leveler]. From then on, it can be tuned with SetLevel independently
+// of its parent and siblings.
+func WithIsolatedChildren() Option {
+	return func(o *options) {
+		o.isolatedChildren = true
+	}
+}
+
+// WithHandleFiltering makes Handle drop records below the threshold, in
+// addition to Enabled reporting them as disabled.
+//
+// This is needed when the handler is used by code that calls Handle without
+// consulting Enabled first, such as some fan-out handlers, which would
+// otherwise let records leak past the override.
+func WithHandleFiltering() Option {
+	return func(o *options) {
+		o.handleFiltering = true
+	}
+}
+
+// DefaultOverriddenKey is the attribute key used by [WithOverrideAnnotation]
+// when no key is given.
+const DefaultOverriddenKey = "log.overridden"
+
+// WithOverrideAnnotation adds a boolean attribute with the given key, or
+// [DefaultOverriddenKey] if key is empty, to records that pass only because
+// of the override, i.e. that the underlying handler would have rejected.
+//
+// This lets downstream analysis tell incident-time verbose logs apart from
+// normal traffic.
+func WithOverrideAnnotation(key string) Option {
+	if key == "" {
+		key = DefaultOverriddenKey
+	}
+	return func(o *options) {
+		o.overriddenKey = key
+	}
+}
+
+// DefaultThresholdKey is the attribute key used by [WithThresholdAttr] when
+// no key is given.
+const DefaultThresholdKey = "log.threshold"
+
+// WithThresholdAttr adds an attribute with the given key, or
+// [DefaultThresholdKey] if key is empty, holding the effective threshold at
+// emission time, e.g. "log.threshold=DEBUG", to every record.
+//
+// The threshold is the level of the override, or the level of the
+// underlying handler if no override is set. This helps correlating log
+// volume changes with level changes.
+func WithThresholdAttr(key string) Option {
+	if key == "" {
+		key = DefaultThresholdKey
+	}
+	return func(o *options) {
+		o.thresholdKey = key
+	}
+}
+
+// WithDeadlineGuard suppresses records below minLevel when the deadline of
+// the context passed to the logger is less than margin away, or already
+// exceeded, trading diagnostics for latency on the critical path.
+//
+// It only applies to contexts with a deadline, so the context-aware logging
+// methods such as [slog.Logger.DebugContext] must be used.
+func WithDeadlineGuard(margin time.Duration, minLevel slog.Level) Option {
+	return func(o *options) {
+		o.deadlineMargin = margin
+		o.deadlineLevel = minLevel
+	}
+}
@@ -0,0 +1,134 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+)
+
+// OriginKind is what determined the effective level of a handler.
+type OriginKind int
+
+const (
+	// OriginHandler is the level of the wrapped handler, in the absence of
+	// any override.
+	OriginHandler OriginKind = iota
+	// OriginOverride is an override of the handler, set at Origin.Layer.
+	OriginOverride
+	// OriginGlobal is the override set with [SetGlobalLevel].
+	OriginGlobal
+	// OriginForced is force-all mode, set with [OverrideHandler.ForceAll].
+	OriginForced
+	// OriginMuted is a muted handler, set with [OverrideHandler.Mute].
+	OriginMuted
+)
+
+// Origin describes what determined the effective level of a handler, as
+// resolved by [OverrideHandler.EffectiveLevel].
+type Origin struct {
+	Kind OriginKind
+	// Layer is the layer of the override in effect if Kind is
+	// OriginOverride.
+	Layer Layer
+	// Source is the source of the override in effect if Kind is
+	// OriginOverride, such as [SourceHTTP].
+	Source string
+}
+
+// String returns a description such as "override at layer remote", followed
+// by the source, if any, as in "override at layer base from http-admin".
+func (o Origin) String() string {
+	switch o.Kind {
+	case OriginOverride:
+		if o.Source != "" {
+			return "override at layer " + o.Layer.String() + " from " + o.Source
+		}
+		return "override at layer " + o.Layer.String()
+	case OriginGlobal:
+		return "global override"
+	case OriginForced:
+		return "force-all mode"
+	case OriginMuted:
+		return "muted"
+	default:
+		return "wrapped handler"
+	}
+}
+
+// String returns the name of a predefined layer, such as "remote", or the
+// number of any other layer.
+func (l Layer) String() string {
+	switch l {
+	case LayerSchedule:
+		return "schedule"
+	case LayerBase:
+		return "base"
+	case LayerMaintenance:
+		return "maintenance"
+	case LayerRemote:
+		return "remote"
+	case LayerPanic:
+		return "panic"
+	case LayerEmergency:
+		return "emergency"
+	case LayerShutdown:
+		return "shutdown"
+	default:
+		return strconv.Itoa(int(l))
+	}
+}
+
+// EffectiveLevel resolves the level records are compared with and reports
+// what determined it, considering, in order of precedence, muting,
+// force-all mode, the global override, the overrides of all layers, and the
+// level of the wrapped handler. It is meant for debugging the
+// configuration; records may still be suppressed by protective options
+// such as [WithThroughputBudget], or let through by rules.
+//
+// A muted handler reports the highest level and a handler in force-all mode
+// the lowest.
+func (h *OverrideHandler) EffectiveLevel() (slog.Level, Origin) {
+	switch {
+	case h.state.muted.Load():
+		return slog.Level(math.MaxInt32), Origin{Kind: OriginMuted}
+	case h.state.forced.Load():
+		return slog.Level(math.MinInt32), Origin{Kind: OriginForced}
+	}
+	if leveler, ok := globalState.load(); ok {
+		return leveler.Level(), Origin{Kind: OriginGlobal}
+	}
+	if layer, leveler, source, ok := h.state.topLayer(); ok {
+		return leveler.Level(), Origin{Kind: OriginOverride, Layer: layer, Source: source}
+	}
+	return handlerLevel(context.Background(), h.wrapped()), Origin{Kind: OriginHandler}
+}
+
+// EffectiveLevel resolves the effective level of the handler registered
+// under scope as [OverrideHandler.EffectiveLevel] does, and returns false if
+// no handler is registered under scope.
+func (r *Registry) EffectiveLevel(scope string) (slog.Level, Origin, bool) {
+	h, ok := r.Handler(scope)
+	if !ok {
+		return 0, Origin{}, false
+	}
+	level, origin := h.EffectiveLevel()
+	return level, origin, true
+}
+
+// topLayer returns the highest layer with an override, the override and its
+// source.
+func (s *levelState) topLayer() (Layer, slog.Leveler, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var (
+		top   Layer
+		found bool
+	)
+	for layer := range s.layers {
+		if !found || layer > top {
+			top, found = layer, true
+		}
+	}
+	return top, s.layers[top], s.sources[top], found
+}
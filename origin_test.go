@@ -0,0 +1,44 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"math"
+	"testing"
+)
+
+// TestEffectiveLevel verifies that the effective level is reported along with what determined it
+func TestEffectiveLevel(t *testing.T) {
+	handler := New(slog.NewTextHandler(nil, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	registry := NewRegistry()
+	registry.Register("db", handler)
+
+	check := func(wantLevel slog.Level, wantOrigin string) {
+		t.Helper()
+		level, origin, ok := registry.EffectiveLevel("db")
+		if !ok || level != wantLevel || origin.String() != wantOrigin {
+			t.Errorf("EffectiveLevel = %v, %q, %v, want %v, %q", level, origin, ok, wantLevel, wantOrigin)
+		}
+	}
+
+	check(slog.LevelWarn, "wrapped handler")
+	handler.SetLevel(slog.LevelInfo)
+	check(slog.LevelInfo, "override at layer base from code")
+	handler.SetLayerLevel(LayerRemote, slog.LevelDebug)
+	check(slog.LevelDebug, "override at layer remote from code")
+	handler.SetLayerLevel(Layer(120), slog.LevelError)
+	check(slog.LevelError, "override at layer 120 from code")
+
+	SetGlobalLevel(slog.LevelWarn)
+	check(slog.LevelWarn, "global override")
+	ClearGlobalLevel()
+
+	handler.ForceAll()
+	check(slog.Level(math.MinInt32), "force-all mode")
+	handler.EndForceAll()
+	handler.Mute()
+	check(slog.Level(math.MaxInt32), "muted")
+
+	if _, _, ok := registry.EffectiveLevel("missing"); ok {
+		t.Error("EffectiveLevel of an unregistered scope should return false")
+	}
+}
@@ -0,0 +1,60 @@
+package slogleveloverride
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// LayerPanic is the layer of the diagnostic window opened by
+// [OverrideHandler.DebugAfterPanic]. It is above [LayerRemote] and below
+// [LayerEmergency], so manual changes during an incident still win.
+const LayerPanic Layer = 150
+
+// DebugAfterPanic is meant to be called from a recover block. It switches
+// the handler to Debug at [LayerPanic] for window, capturing as much context
+// as possible right after the panic, after which the layer is cleared. A
+// panic during an open window extends it.
+//
+// If flush is not nil, the records kept by [WithSuppressedBuffer] are
+// written to it, as with [OverrideHandler.DumpSuppressed], and removed from
+// the buffer, preserving the context that led to the panic.
+//
+// The switch is subject to the policy set with [SetPolicy].
+func (h *OverrideHandler) DebugAfterPanic(window time.Duration, flush io.Writer) error {
+	if checkPolicy("", slog.LevelDebug) == nil {
+		s := h.state
+		s.mu.Lock()
+		s.panicGen++
+		gen := s.panicGen
+		s.mu.Unlock()
+		s.setLayer(LayerPanic, "panic", slog.LevelDebug)
+		h.checkMismatch(context.Background())
+		time.AfterFunc(window, func() { s.endPanicWindow(gen) })
+	}
+	if flush == nil || h.opts.suppressed == nil {
+		return nil
+	}
+	for _, line := range h.opts.suppressed.drain() {
+		if _, err := flush.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endPanicWindow clears [LayerPanic], unless another window was opened
+// after the one numbered gen.
+func (s *levelState) endPanicWindow(gen uint64) {
+	s.mu.Lock()
+	if s.panicGen != gen {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.layers, LayerPanic)
+	delete(s.sources, LayerPanic)
+	top, watchers := s.republish()
+	s.mu.Unlock()
+	notify(watchers, top)
+}
@@ -0,0 +1,57 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestDebugAfterPanic verifies that Debug is enabled for the window and the suppressed records are flushed
+func TestDebugAfterPanic(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelInfo, WithSuppressedBuffer(10, 0))
+	logger := slog.New(handler)
+	logger.Debug("debug before panic")
+
+	var flushed bytes.Buffer
+	func() {
+		defer func() {
+			if recover() != nil {
+				if err := handler.DebugAfterPanic(50*time.Millisecond, &flushed); err != nil {
+					t.Errorf("DebugAfterPanic returned %v", err)
+				}
+			}
+		}()
+		panic("boom")
+	}()
+	logger.Debug("debug after panic")
+
+	if !strings.Contains(flushed.String(), "debug before panic") {
+		t.Errorf("flushed %q, want the suppressed record", flushed.String())
+	}
+	var dump bytes.Buffer
+	handler.DumpSuppressed(&dump)
+	if dump.Len() != 0 {
+		t.Errorf("buffer still holds %q after the flush", dump.String())
+	}
+
+	if source, _ := handler.LayerSource(LayerPanic); source != "panic" {
+		t.Errorf("source of the panic layer is %q during the window", source)
+	}
+	waitFor(t, "end of the window", func() bool {
+		_, ok := handler.LayerLeveler(LayerPanic)
+		return !ok
+	})
+	if source, ok := handler.LayerSource(LayerPanic); ok {
+		t.Errorf("source of the panic layer is still %q after the window", source)
+	}
+	logger.Debug("debug after window")
+
+	assertHandler.AssertMessage("debug after panic")
+}
@@ -0,0 +1,89 @@
+package slogleveloverride
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// patternLevel is a level set for a scope pattern with
+// [Registry.SetPatternLevel].
+type patternLevel struct {
+	pattern string
+	level   slog.Leveler
+}
+
+// SetPatternLevel sets level for the scope pattern, in which '*' matches any
+// sequence of characters, such as "db.*" or "*.cache".
+//
+// The level is set on every registered handler matching pattern, and on
+// handlers registered later. If several patterns match a name, the most
+// specific one, with the most literal characters, wins, and among equally
+// specific patterns the one set last. The resolved levels are validated
+// like with [Registry.Apply]: if any is rejected by the policy set with
+// [SetPolicy], an error is returned and nothing is changed.
+func (r *Registry) SetPatternLevel(pattern string, level slog.Leveler) error {
+	if level == nil {
+		return fmt.Errorf("slogleveloverride: nil level for pattern %q", pattern)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := r.patterns
+	r.patterns = append(slices.DeleteFunc(slices.Clone(r.patterns), func(p patternLevel) bool {
+		return p.pattern == pattern
+	}), patternLevel{pattern: pattern, level: level})
+
+	changes := make(map[string]slog.Leveler)
+	for name := range r.handlers {
+		if globMatch(pattern, name) {
+			changes[name], _ = r.patternLevel(name)
+		}
+	}
+	if _, err := r.apply(changes); err != nil {
+		r.patterns = previous
+		return err
+	}
+	return nil
+}
+
+// ClearPatternLevel removes the level set for pattern, so that handlers
+// registered later no longer get it. The overrides of handlers already
+// registered are left unchanged.
+func (r *Registry) ClearPatternLevel(pattern string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.patterns = slices.DeleteFunc(slices.Clone(r.patterns), func(p patternLevel) bool {
+		return p.pattern == pattern
+	})
+}
+
+// GetLevels returns the effective levels of the registered handlers whose
+// name matches pattern, in which '*' matches any sequence of characters.
+func (r *Registry) GetLevels(pattern string) map[string]slog.Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	levels := make(map[string]slog.Level)
+	for name, h := range r.handlers {
+		if globMatch(pattern, name) {
+			levels[name] = h.effectiveLevel(context.Background())
+		}
+	}
+	return levels
+}
+
+// patternLevel returns the level of the most specific pattern matching
+// name. r.mu must be held.
+func (r *Registry) patternLevel(name string) (slog.Leveler, bool) {
+	var (
+		best      slog.Leveler
+		bestScore = -1
+	)
+	for _, p := range r.patterns {
+		if score := scopeSpecificity(p.pattern, name); score >= 0 && score >= bestScore {
+			best, bestScore = p.level, score
+		}
+	}
+	return best, bestScore >= 0
+}
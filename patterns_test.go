@@ -0,0 +1,63 @@
+package slogleveloverride
+
+import (
+	"errors"
+	"log/slog"
+	"maps"
+	"testing"
+)
+
+// TestSetPatternLevel verifies that the most specific pattern wins, also for handlers registered later
+func TestSetPatternLevel(t *testing.T) {
+	registry := NewRegistry()
+	newHandler := func(name string) *OverrideHandler {
+		h := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+		registry.Register(name, h)
+		return h
+	}
+	pool, query, http := newHandler("db.pool"), newHandler("db.query"), newHandler("http")
+
+	if !registry.SetLevel("db.*", slog.LevelDebug) {
+		t.Fatal("SetLevel with a pattern failed")
+	}
+	if err := registry.SetPatternLevel("db.pool*", slog.LevelWarn); err != nil {
+		t.Fatal(err)
+	}
+	// Setting the less specific pattern again does not override the more specific one
+	registry.SetLevel("db.*", slog.LevelError)
+
+	want := map[string]slog.Level{"db.pool": slog.LevelWarn, "db.query": slog.LevelError}
+	if got := registry.GetLevels("db.*"); !maps.Equal(got, want) {
+		t.Errorf("GetLevels = %v, want %v", got, want)
+	}
+	if level, _ := http.Level(); level != slog.LevelInfo {
+		t.Errorf("http got level %v from a pattern it does not match", level)
+	}
+
+	conn := newHandler("db.pool.conn")
+	if level, _ := conn.Level(); level != slog.LevelWarn {
+		t.Errorf("handler registered later has level %v, want %v", level, slog.LevelWarn)
+	}
+	registry.ClearPatternLevel("db.pool*")
+	if level, _ := newHandler("db.pool.idle").Level(); level != slog.LevelError {
+		t.Errorf("handler registered after clearing has level %v, want %v", level, slog.LevelError)
+	}
+
+	errRejected := errors.New("rejected")
+	SetPolicy(func(target string, level slog.Leveler) error {
+		if target == "db.query" {
+			return errRejected
+		}
+		return nil
+	})
+	defer SetPolicy(nil)
+	if err := registry.SetPatternLevel("db.*", slog.LevelDebug); !errors.Is(err, errRejected) {
+		t.Errorf("SetPatternLevel returned %v, want %v", err, errRejected)
+	}
+	if level, _ := pool.Level(); level != slog.LevelWarn {
+		t.Errorf("db.pool changed despite the rejection: %v", level)
+	}
+	if level, _ := query.Level(); level != slog.LevelError {
+		t.Errorf("db.query changed despite the rejection: %v", level)
+	}
+}
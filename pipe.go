@@ -0,0 +1,139 @@
+package slogleveloverride
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ControlFDEnvVar is the environment variable through which
+// [StartWithControl] tells a child process the file descriptors of its
+// control channel, read by [ServeParentControl].
+const ControlFDEnvVar = "LOG_CONTROL_FD"
+
+// ChildControl is the parent side of the control channel of a child process
+// started with [StartWithControl]. Commands use the protocol of
+// [ControlServer].
+//
+// A ChildControl is safe for concurrent use.
+type ChildControl struct {
+	mu        sync.Mutex
+	requests  *os.File
+	responses *os.File
+	reader    *bufio.Reader
+}
+
+// StartWithControl starts cmd with a control channel made of two pipes
+// passed as extra files, so that a supervisor can change the levels of a
+// worker process at runtime. The child serves the channel with
+// [ServeParentControl].
+//
+// The pipes are not supported on Windows, where starting cmd fails.
+func StartWithControl(cmd *exec.Cmd) (*ChildControl, error) {
+	childRequests, requests, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	responses, childResponses, err := os.Pipe()
+	if err != nil {
+		childRequests.Close()
+		requests.Close()
+		return nil, err
+	}
+
+	fd := 3 + len(cmd.ExtraFiles)
+	cmd.ExtraFiles = append(cmd.ExtraFiles, childRequests, childResponses)
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%d,%d", ControlFDEnvVar, fd, fd+1))
+	err = cmd.Start()
+	childRequests.Close()
+	childResponses.Close()
+	if err != nil {
+		requests.Close()
+		responses.Close()
+		return nil, err
+	}
+	return &ChildControl{requests: requests, responses: responses, reader: bufio.NewReader(responses)}, nil
+}
+
+// Send sends command, such as "set debug" or "get db", and returns the
+// payload of the response. Error responses are returned as errors.
+func (c *ChildControl) Send(command string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := io.WriteString(c.requests, command+"\n"); err != nil {
+		return "", err
+	}
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\n")
+	if msg, found := strings.CutPrefix(line, "error "); found {
+		return "", errors.New(msg)
+	}
+	payload, found := strings.CutPrefix(line, "ok")
+	if !found {
+		return "", fmt.Errorf("slogleveloverride: unexpected response %q", line)
+	}
+	payload = strings.TrimPrefix(payload, " ")
+	if !strings.HasPrefix(command, "dump") {
+		return payload, nil
+	}
+	n, err := strconv.Atoi(payload)
+	if err != nil {
+		return "", fmt.Errorf("slogleveloverride: unexpected line count %q", payload)
+	}
+	lines := make([]string, n)
+	for i := range lines {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		lines[i] = strings.TrimSuffix(line, "\n")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Close closes both pipes of the control channel, which ends
+// [ServeParentControl] in the child.
+func (c *ChildControl) Close() error {
+	return errors.Join(c.requests.Close(), c.responses.Close())
+}
+
+// ServeParentControl serves the control channel set up by the parent process
+// with [StartWithControl] until the parent closes it. If the process was not
+// started with a control channel, it returns nil right away.
+//
+// [ControlFDEnvVar] is removed from the environment, so that processes
+// started by this one do not inherit it.
+func ServeParentControl(s *ControlServer) error {
+	value := os.Getenv(ControlFDEnvVar)
+	if value == "" {
+		return nil
+	}
+	os.Unsetenv(ControlFDEnvVar)
+	requestFD, responseFD, found := strings.Cut(value, ",")
+	in, err1 := strconv.Atoi(requestFD)
+	out, err2 := strconv.Atoi(responseFD)
+	if !found || err1 != nil || err2 != nil {
+		return fmt.Errorf("slogleveloverride: invalid %s %q", ControlFDEnvVar, value)
+	}
+	r := os.NewFile(uintptr(in), "control-requests")
+	w := os.NewFile(uintptr(out), "control-responses")
+	if r == nil || w == nil {
+		return fmt.Errorf("slogleveloverride: invalid %s %q", ControlFDEnvVar, value)
+	}
+	defer r.Close()
+	defer w.Close()
+	return s.ServeStream(r, w)
+}
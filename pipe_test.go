@@ -0,0 +1,65 @@
+//go:build unix
+
+package slogleveloverride
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// TestControlChild is the child process of TestStartWithControl
+func TestControlChild(t *testing.T) {
+	if os.Getenv(ControlFDEnvVar) == "" {
+		t.Skip("only run as child process")
+	}
+	server := &ControlServer{Handler: NewWithLevel(slog.DiscardHandler, slog.LevelInfo)}
+	if err := ServeParentControl(server); err != nil {
+		t.Fatal(err)
+	}
+	if value := os.Getenv(ControlFDEnvVar); value != "" {
+		t.Fatalf("%s=%q left in the environment", ControlFDEnvVar, value)
+	}
+}
+
+// TestStartWithControl verifies that a parent process changes the level of a child through the pipes
+func TestStartWithControl(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestControlChild$")
+	control, err := StartWithControl(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := control.Send("get"); err != nil || got != "INFO" {
+		t.Errorf("get returned %q, %v, want INFO", got, err)
+	}
+	if _, err := control.Send("set debug"); err != nil {
+		t.Errorf("set returned %v", err)
+	}
+	if got, err := control.Send("get"); err != nil || got != "DEBUG" {
+		t.Errorf("get returned %q, %v, want DEBUG", got, err)
+	}
+	if _, err := control.Send("get missing"); err == nil {
+		t.Error("get of an unknown scope should fail")
+	}
+
+	if err := control.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := control.responses.Close(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("responses pipe left open by Close: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("child failed: %v", err)
+	}
+}
+
+// TestServeParentControlWithoutChannel verifies that a process started without a control channel is not served
+func TestServeParentControlWithoutChannel(t *testing.T) {
+	t.Setenv(ControlFDEnvVar, "")
+	if err := ServeParentControl(&ControlServer{}); err != nil {
+		t.Errorf("ServeParentControl returned %v", err)
+	}
+}
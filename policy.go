@@ -0,0 +1,49 @@
+package slogleveloverride
+
+import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// GlobalTarget is the target passed to the policy for changes made with
+// [SetGlobalLevel].
+const GlobalTarget = "*"
+
+// Policy validates a level change before it is made. target is the registry
+// name of the changed handler, [GlobalTarget] for the process-wide
+// override, or "" for a handler changed directly rather than by name.
+// Returning an error rejects the change.
+type Policy func(target string, level slog.Leveler) error
+
+// policy holds the policy set with SetPolicy.
+var policy atomic.Pointer[Policy]
+
+// SetPolicy sets the process-wide policy consulted by every path setting a
+// level override: the SetLevel functions and methods, layers, the global
+// override, specs, flags and the [ControlServer]. This lets platform teams
+// forbid, e.g., Debug in production, or restrict the scopes that may be
+// changed. A nil policy accepts every change.
+//
+// Paths that report errors return the policy error wrapped; the others
+// leave the override unchanged. Clearing an override, and restoring an
+// earlier one, is not subject to the policy.
+func SetPolicy(p Policy) {
+	if p == nil {
+		policy.Store(nil)
+		return
+	}
+	policy.Store(&p)
+}
+
+// checkPolicy returns the error of the policy for the change, if rejected.
+func checkPolicy(target string, level slog.Leveler) error {
+	p := policy.Load()
+	if p == nil || level == nil {
+		return nil
+	}
+	if err := (*p)(target, level); err != nil {
+		return fmt.Errorf("slogleveloverride: level change of %q rejected by policy: %w", target, err)
+	}
+	return nil
+}
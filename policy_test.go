@@ -0,0 +1,54 @@
+package slogleveloverride
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+// TestPolicy verifies that level changes rejected by the policy are not made
+func TestPolicy(t *testing.T) {
+	errNoDebug := errors.New("debug is not allowed")
+	var targets []string
+	SetPolicy(func(target string, level slog.Leveler) error {
+		targets = append(targets, target)
+		if level.Level() <= slog.LevelDebug {
+			return errNoDebug
+		}
+		return nil
+	})
+	defer SetPolicy(nil)
+
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	registry := NewRegistry()
+	registry.Register("db", handler)
+
+	handler.SetLevel(slog.LevelDebug)
+	if SetLevel(handler, slog.LevelDebug) {
+		t.Error("SetLevel should return false when rejected")
+	}
+	if registry.SetLevel("db", slog.LevelDebug) {
+		t.Error("Registry.SetLevel should return false when rejected")
+	}
+	if err := registry.ApplySpec(Spec{{Scope: "*", Level: slog.LevelDebug}}); !errors.Is(err, errNoDebug) {
+		t.Errorf("ApplySpec returned %v, want %v", err, errNoDebug)
+	}
+	if level := handler.IncreaseVerbosity(); level != slog.LevelInfo {
+		t.Errorf("IncreaseVerbosity returned %v, want %v", level, slog.LevelInfo)
+	}
+	SetGlobalLevel(slog.LevelDebug)
+	if _, ok := GlobalLevel(); ok {
+		ClearGlobalLevel()
+		t.Error("SetGlobalLevel should be rejected")
+	}
+	if level, _ := handler.Level(); level != slog.LevelInfo {
+		t.Fatalf("level is %v after rejected changes, want %v", level, slog.LevelInfo)
+	}
+
+	if !registry.SetLevel("db", slog.LevelError) {
+		t.Fatal("Registry.SetLevel should accept Error")
+	}
+	if got := targets[len(targets)-1]; got != "db" {
+		t.Errorf("policy target is %q, want %q", got, "db")
+	}
+}
@@ -0,0 +1,118 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PressureLeveler is a [slog.Leveler] that sheds verbose records while the
+// process is under memory or CPU pressure.
+//
+// Its level is Base, raised to at least Shed while under pressure. Pressure
+// starts when memory use reaches MemoryHigh of the limit set with GOMEMLIMIT,
+// or when CPU use reaches CPUHigh of the capacity given by GOMAXPROCS. It
+// ends once both fall below MemoryLow and CPULow respectively. Memory
+// pressure is only considered when a memory limit is set.
+//
+// Pressure is sampled from [runtime/metrics] at most once per Interval,
+// during calls to Level. The CPU figures of the runtime are estimates and
+// are mostly refreshed by garbage collections.
+//
+// The fields must not be changed after the first call to Level.
+type PressureLeveler struct {
+	Base slog.Leveler
+	Shed slog.Level
+
+	MemoryHigh, MemoryLow float64
+	CPUHigh, CPULow       float64
+	Interval              time.Duration
+
+	// sample returns the memory and CPU use as fractions of their limits.
+	sample func() (memory, cpu float64)
+
+	nextSample atomic.Int64
+	shedding   atomic.Bool
+
+	mu      sync.Mutex
+	samples [4]metrics.Sample
+	lastCPU [2]float64
+}
+
+// NewPressureLeveler creates a [PressureLeveler] using base normally and shed
+// under pressure, with thresholds of 90% to start and 80% to end pressure,
+// sampled every second.
+func NewPressureLeveler(base slog.Leveler, shed slog.Level) *PressureLeveler {
+	return &PressureLeveler{
+		Base:       base,
+		Shed:       shed,
+		MemoryHigh: 0.9,
+		MemoryLow:  0.8,
+		CPUHigh:    0.9,
+		CPULow:     0.8,
+		Interval:   time.Second,
+	}
+}
+
+// Level returns Base, or Shed if higher and the process is under pressure.
+func (p *PressureLeveler) Level() slog.Level {
+	now := time.Now().UnixNano()
+	if next := p.nextSample.Load(); now >= next && p.nextSample.CompareAndSwap(next, now+int64(p.Interval)) {
+		p.update()
+	}
+	level := p.Base.Level()
+	if p.shedding.Load() {
+		level = max(level, p.Shed)
+	}
+	return level
+}
+
+// UnderPressure reports whether the leveler currently sheds records.
+func (p *PressureLeveler) UnderPressure() bool {
+	return p.shedding.Load()
+}
+
+// update samples the pressure and starts or ends shedding.
+func (p *PressureLeveler) update() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sample := p.sample
+	if sample == nil {
+		sample = p.runtimeSample
+	}
+	memory, cpu := sample()
+
+	if p.shedding.Load() {
+		if memory < p.MemoryLow && cpu < p.CPULow {
+			p.shedding.Store(false)
+		}
+	} else if memory >= p.MemoryHigh || cpu >= p.CPUHigh {
+		p.shedding.Store(true)
+	}
+}
+
+// runtimeSample reads the memory and CPU use from runtime/metrics.
+func (p *PressureLeveler) runtimeSample() (memory, cpu float64) {
+	p.samples[0].Name = "/memory/classes/total:bytes"
+	p.samples[1].Name = "/memory/classes/heap/released:bytes"
+	p.samples[2].Name = "/cpu/classes/total:cpu-seconds"
+	p.samples[3].Name = "/cpu/classes/idle:cpu-seconds"
+	metrics.Read(p.samples[:])
+
+	if limit := debug.SetMemoryLimit(-1); limit > 0 && limit < math.MaxInt64 {
+		used := p.samples[0].Value.Uint64() - p.samples[1].Value.Uint64()
+		memory = float64(used) / float64(limit)
+	}
+
+	total, idle := p.samples[2].Value.Float64(), p.samples[3].Value.Float64()
+	if elapsed := total - p.lastCPU[0]; p.lastCPU[0] > 0 && elapsed > 0 {
+		cpu = 1 - (idle-p.lastCPU[1])/elapsed
+	}
+	p.lastCPU = [2]float64{total, idle}
+	return memory, cpu
+}
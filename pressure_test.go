@@ -0,0 +1,56 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+)
+
+// TestPressureLeveler verifies shedding with hysteresis between the high and low thresholds
+func TestPressureLeveler(t *testing.T) {
+	var memory, cpu float64
+	leveler := NewPressureLeveler(slog.LevelDebug, slog.LevelWarn)
+	leveler.Interval = 0
+	leveler.sample = func() (float64, float64) { return memory, cpu }
+
+	steps := []struct {
+		memory, cpu float64
+		want        slog.Level
+	}{
+		{0.5, 0.5, slog.LevelDebug},
+		{0.95, 0.5, slog.LevelWarn},
+		// Between the thresholds, the previous state is kept
+		{0.85, 0.5, slog.LevelWarn},
+		{0.5, 0.5, slog.LevelDebug},
+		{0.5, 0.85, slog.LevelDebug},
+		{0.5, 0.92, slog.LevelWarn},
+		{0.5, 0.1, slog.LevelDebug},
+	}
+	for i, step := range steps {
+		memory, cpu = step.memory, step.cpu
+		if got := leveler.Level(); got != step.want {
+			t.Fatalf("step %d: Level = %v, want %v", i, got, step.want)
+		}
+		if got, want := leveler.UnderPressure(), step.want == slog.LevelWarn; got != want {
+			t.Fatalf("step %d: UnderPressure = %v, want %v", i, got, want)
+		}
+	}
+
+	// The base level is kept when it is above the shed level
+	leveler.Base = slog.LevelError
+	memory = 1
+	if got := leveler.Level(); got != slog.LevelError {
+		t.Fatalf("Level = %v, want %v", got, slog.LevelError)
+	}
+}
+
+// TestPressureLevelerRuntimeSample verifies that runtime metrics can be sampled
+func TestPressureLevelerRuntimeSample(t *testing.T) {
+	leveler := NewPressureLeveler(slog.LevelInfo, slog.LevelWarn)
+	if got := leveler.Level(); got != slog.LevelInfo && got != slog.LevelWarn {
+		t.Fatalf("Level = %v", got)
+	}
+	memory, cpu := leveler.runtimeSample()
+	if memory < 0 || cpu < 0 || cpu > 1 {
+		t.Fatalf("runtimeSample returned (%v, %v)", memory, cpu)
+	}
+}
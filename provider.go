@@ -0,0 +1,187 @@
+package slogleveloverride
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Update is a level change delivered by a [Source].
+type Update struct {
+	// Scope is the name of the registered handler the update applies to.
+	Scope string
+	// Level is the new level of Scope, or nil to withdraw the level the
+	// source set for it.
+	Level slog.Leveler
+}
+
+// Source provides level overrides from a configuration backend, such as a
+// file, a key-value store or a feature flag service. Implementations live
+// outside this package and are combined by a [SourceManager].
+type Source interface {
+	// Start starts watching the backend. Updates are delivered on the
+	// channel returned by Levels until Stop is called.
+	Start(ctx context.Context) error
+	// Levels returns the channel on which updates are delivered. The
+	// source closes it once stopped.
+	Levels() <-chan Update
+	// Stop stops watching the backend.
+	Stop() error
+}
+
+// SourceManager merges the updates of several [Source]s and applies them to
+// the handlers of a [Registry], at [LayerRemote].
+//
+// Each source keeps its own level per scope. If several sources set a level
+// for the same scope, the source added last wins, and withdrawing its level
+// exposes that of the source added before it. Handlers registered later
+// receive the merged level of their name on registration. Changes rejected
+// by the policy set with [SetPolicy] are ignored.
+//
+// A SourceManager is safe for concurrent use.
+type SourceManager struct {
+	registry *Registry
+
+	mu      sync.Mutex
+	sources []*managedSource
+	wg      sync.WaitGroup
+}
+
+// managedSource is a [Source] added to a [SourceManager], with the levels
+// it delivered by scope.
+type managedSource struct {
+	name   string
+	source Source
+	levels map[string]slog.Leveler
+}
+
+// NewSourceManager creates a [SourceManager] applying updates to the
+// handlers of r.
+func NewSourceManager(r *Registry) *SourceManager {
+	m := &SourceManager{registry: r}
+	r.AddObserver(sourceObserver{m})
+	return m
+}
+
+// Add starts source and applies its updates until it is stopped. name
+// identifies the source in errors and must be unique.
+func (m *SourceManager) Add(ctx context.Context, name string, source Source) error {
+	m.mu.Lock()
+	for _, s := range m.sources {
+		if s.name == name {
+			m.mu.Unlock()
+			return fmt.Errorf("slogleveloverride: source %q already added", name)
+		}
+	}
+	s := &managedSource{name: name, source: source, levels: make(map[string]slog.Leveler)}
+	m.sources = append(m.sources, s)
+	m.mu.Unlock()
+
+	if err := source.Start(ctx); err != nil {
+		m.remove(s)
+		return fmt.Errorf("slogleveloverride: starting source %q: %w", name, err)
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for u := range source.Levels() {
+			m.update(s, u)
+		}
+	}()
+	return nil
+}
+
+// Stop stops all sources and waits until their last updates are applied.
+// The levels they set remain in effect.
+func (m *SourceManager) Stop() error {
+	m.mu.Lock()
+	sources := m.sources
+	m.mu.Unlock()
+
+	var errs []error
+	for _, s := range sources {
+		if err := s.source.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("slogleveloverride: stopping source %q: %w", s.name, err))
+		}
+	}
+	m.wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Level returns the merged level of scope, or false if no source sets one.
+func (m *SourceManager) Level(scope string) (slog.Leveler, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.merged(scope)
+}
+
+// update records u as delivered by s and applies the resulting merged level.
+// The level is applied with m.mu held, so that concurrent updates of the
+// same scope are applied in order.
+func (m *SourceManager) update(s *managedSource, u Update) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if u.Level != nil {
+		s.levels[u.Scope] = u.Level
+	} else {
+		delete(s.levels, u.Scope)
+	}
+	if h, found := m.registry.Handler(u.Scope); found {
+		level, ok := m.merged(u.Scope)
+		applySourceLevel(u.Scope, h, level, ok)
+	}
+}
+
+// remove removes s from the sources of m.
+func (m *SourceManager) remove(s *managedSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, other := range m.sources {
+		if other == s {
+			m.sources = append(m.sources[:i:i], m.sources[i+1:]...)
+			return
+		}
+	}
+}
+
+// merged returns the level of scope set by the source added last. m.mu must
+// be held.
+func (m *SourceManager) merged(scope string) (slog.Leveler, bool) {
+	for i := len(m.sources) - 1; i >= 0; i-- {
+		if l, ok := m.sources[i].levels[scope]; ok {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// applySourceLevel sets or clears the [LayerRemote] override of h,
+// registered under name.
+func applySourceLevel(name string, h *OverrideHandler, level slog.Leveler, ok bool) {
+	if !ok {
+		h.ClearLayer(LayerRemote)
+		return
+	}
+	if checkPolicy(name, level) == nil {
+		h.state.setLayer(LayerRemote, "remote", level)
+		h.checkMismatch(context.Background())
+	}
+}
+
+// sourceObserver applies the merged levels of a [SourceManager] to handlers
+// as they are registered.
+type sourceObserver struct {
+	m *SourceManager
+}
+
+func (o sourceObserver) OnRegister(name string, h *OverrideHandler) {
+	if level, ok := o.m.Level(name); ok {
+		applySourceLevel(name, h, level, true)
+	}
+}
+
+func (sourceObserver) OnLevelChange(string, slog.Leveler) {}
+
+func (sourceObserver) OnRemove(string) {}
@@ -0,0 +1,92 @@
+package slogleveloverride
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+// chanSource is a [Source] delivering the updates sent on its channel
+type chanSource struct {
+	updates  chan Update
+	startErr error
+}
+
+func newChanSource() *chanSource {
+	return &chanSource{updates: make(chan Update)}
+}
+
+func (s *chanSource) Start(context.Context) error { return s.startErr }
+func (s *chanSource) Levels() <-chan Update       { return s.updates }
+func (s *chanSource) Stop() error {
+	close(s.updates)
+	return nil
+}
+
+// TestSourceManager verifies that updates of several sources are merged by precedence
+func TestSourceManager(t *testing.T) {
+	db := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	registry := NewRegistry()
+	registry.Register("db", db)
+	manager := NewSourceManager(registry)
+
+	file, flags := newChanSource(), newChanSource()
+	if err := manager.Add(t.Context(), "file", file); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Add(t.Context(), "flags", flags); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Add(t.Context(), "file", newChanSource()); err == nil {
+		t.Error("adding a source twice should fail")
+	}
+
+	wantLevel := func(h *OverrideHandler, want slog.Level) {
+		t.Helper()
+		if level, ok := h.Level(); !ok || level != want {
+			t.Errorf("level = %v, %v, want %v", level, ok, want)
+		}
+	}
+
+	// A source receives the next update only once the previous one is
+	// applied, so a no-op update waits for the ones before it
+	flush := func() {
+		file.updates <- Update{Scope: "none"}
+		flags.updates <- Update{Scope: "none"}
+	}
+
+	file.updates <- Update{Scope: "db", Level: slog.LevelWarn}
+	flags.updates <- Update{Scope: "db", Level: slog.LevelDebug}
+	file.updates <- Update{Scope: "db", Level: slog.LevelError}
+	flags.updates <- Update{Scope: "http", Level: slog.LevelDebug}
+	flush()
+	wantLevel(db, slog.LevelDebug)
+
+	flags.updates <- Update{Scope: "db"}
+	flush()
+	wantLevel(db, slog.LevelError)
+
+	http := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	registry.Register("http", http)
+	wantLevel(http, slog.LevelDebug)
+
+	if err := manager.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	db.ClearLayer(LayerRemote)
+	wantLevel(db, slog.LevelInfo)
+}
+
+// TestSourceManagerStartError verifies that a source failing to start is not added
+func TestSourceManagerStartError(t *testing.T) {
+	manager := NewSourceManager(NewRegistry())
+	errUnavailable := errors.New("unavailable")
+	source := &chanSource{startErr: errUnavailable}
+	if err := manager.Add(t.Context(), "kv", source); !errors.Is(err, errUnavailable) {
+		t.Errorf("Add returned %v, want %v", err, errUnavailable)
+	}
+	if err := manager.Add(t.Context(), "kv", newChanSource()); err != nil {
+		t.Errorf("adding after a failed start returned %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// maxRateLimitKeys bounds the number of attribute values tracked by an
+// attribute rate limiter.
+const maxRateLimitKeys = 10000
+
+// attrRateLimit allows at most perSecond records per value of an attribute.
+type attrRateLimit struct {
+	key       string
+	perSecond int
+
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// WithAttrRateLimit allows at most perSecond records per second for each
+// value of the attribute key, e.g. per "request_id" or "host", so that a
+// single hot entity cannot dominate the output. Records without the
+// attribute are not limited.
+//
+// The limit applies in Handle, after the level check. The attribute is
+// looked up among the top-level attributes of the record and those added
+// with WithAttrs outside of any group. Up to 10000 values are tracked at a
+// time; while that many values are active within a second, records with a
+// new value are not limited.
+func WithAttrRateLimit(key string, perSecond int) Option {
+	return func(o *options) {
+		o.rateLimit = &attrRateLimit{
+			key:       key,
+			perSecond: perSecond,
+			windows:   make(map[string]*rateWindow),
+		}
+	}
+}
+
+// allow reports whether another record for value fits the limit.
+func (l *attrRateLimit) allow(value string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.windows[value]
+	if !ok {
+		if len(l.windows) >= maxRateLimitKeys {
+			l.sweep(now)
+			if len(l.windows) >= maxRateLimitKeys {
+				return true
+			}
+		}
+		w = &rateWindow{start: now}
+		l.windows[value] = w
+	}
+	if now.Sub(w.start) >= time.Second {
+		w.start, w.count = now, 0
+	}
+	w.count++
+	return w.count <= l.perSecond
+}
+
+// sweep removes the windows that have expired, at most once per second so
+// that a full limiter does not scan its windows for every new value.
+func (l *attrRateLimit) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Second {
+		return
+	}
+	l.lastSweep = now
+	for value, w := range l.windows {
+		if now.Sub(w.start) >= time.Second {
+			delete(l.windows, value)
+		}
+	}
+}
+
+// rateLimited reports whether record exceeds the attribute rate limit.
+func (h *OverrideHandler) rateLimited(record slog.Record) bool {
+	l := h.opts.rateLimit
+	if l == nil {
+		return false
+	}
+	value, ok := h.lookupAttr(record, l.key)
+	if !ok {
+		return false
+	}
+	return !l.allow(value.String(), time.Now())
+}
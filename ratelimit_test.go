@@ -0,0 +1,82 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestWithAttrRateLimit verifies that records are limited per attribute value
+func TestWithAttrRateLimit(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := New(assertHandler, WithAttrRateLimit("host", 2))
+	logger := slog.New(handler)
+	hostA := logger.With("host", "a")
+
+	for range 3 {
+		hostA.Info("from a")
+		logger.Info("from b", "host", "b")
+		logger.Info("without host")
+	}
+
+	for range 2 {
+		assertHandler.AssertMessage("from a")
+		assertHandler.AssertMessage("from b")
+	}
+	for range 3 {
+		assertHandler.AssertMessage("without host")
+	}
+}
+
+// TestAttrRateLimitWindow verifies that the limit resets every second
+func TestAttrRateLimitWindow(t *testing.T) {
+	limit := &attrRateLimit{key: "host", perSecond: 1, windows: make(map[string]*rateWindow)}
+	now := time.Now()
+
+	if !limit.allow("a", now) {
+		t.Fatal("first record should be allowed")
+	}
+	if limit.allow("a", now.Add(500*time.Millisecond)) {
+		t.Fatal("second record within the window should not be allowed")
+	}
+	if !limit.allow("a", now.Add(time.Second)) {
+		t.Fatal("record in the next window should be allowed")
+	}
+
+	limit.sweep(now.Add(3 * time.Second))
+	if len(limit.windows) != 0 {
+		t.Fatalf("sweep left %d windows", len(limit.windows))
+	}
+}
+
+// TestAttrRateLimitFull verifies that a full limiter stops tracking new values and sweeps once per second
+func TestAttrRateLimitFull(t *testing.T) {
+	limit := &attrRateLimit{key: "request_id", perSecond: 1, windows: make(map[string]*rateWindow)}
+	now := time.Now()
+
+	for i := range maxRateLimitKeys + 100 {
+		limit.allow(strconv.Itoa(i), now)
+	}
+	if len(limit.windows) != maxRateLimitKeys {
+		t.Fatalf("limiter tracks %d values, want %d", len(limit.windows), maxRateLimitKeys)
+	}
+	if !limit.allow("untracked", now) || !limit.allow("untracked", now) {
+		t.Error("records with an untracked value should not be limited")
+	}
+	if !limit.lastSweep.Equal(now) {
+		t.Errorf("last sweep at %v, want %v", limit.lastSweep, now)
+	}
+
+	later := now.Add(time.Second)
+	if !limit.allow("new", later) || limit.allow("new", later) {
+		t.Error("new value should be tracked after the windows expired")
+	}
+	if len(limit.windows) != 1 {
+		t.Errorf("limiter tracks %d values after the sweep, want 1", len(limit.windows))
+	}
+}
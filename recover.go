@@ -0,0 +1,36 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithPanicRecovery recovers from panics raised by user-provided code while
+// deciding whether a record is enabled, such as the Level method of a
+// dynamic [slog.Leveler] or of source rules. The decision then falls back
+// to the underlying handler's Enabled method, and the recovered value is
+// passed to report if not nil. This keeps a misbehaving leveler, e.g. one
+// backed by remote configuration, from taking down logging.
+func WithPanicRecovery(report func(recovered any)) Option {
+	return func(o *options) {
+		o.panicRecovery = true
+		o.panicReport = report
+	}
+}
+
+// guard returns the result of decide, or of the underlying handler's
+// Enabled method if decide panics and panics are recovered.
+func (h *OverrideHandler) guard(ctx context.Context, level slog.Level, decide func() bool) (enabled bool) {
+	if !h.opts.panicRecovery {
+		return decide()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if h.opts.panicReport != nil {
+				h.opts.panicReport(r)
+			}
+			enabled = h.wrapped().Enabled(ctx, level)
+		}
+	}()
+	return decide()
+}
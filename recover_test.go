@@ -0,0 +1,47 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// panickingLeveler is a Leveler whose Level method panics
+type panickingLeveler struct{}
+
+func (panickingLeveler) Level() slog.Level {
+	panic("remote config unavailable")
+}
+
+// TestPanicRecovery verifies that a panicking leveler falls back to the underlying handler
+func TestPanicRecovery(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelInfo, nil)
+	defer assertHandler.AssertEmpty()
+
+	var recovered []any
+	handler := NewWithLevel(assertHandler, panickingLeveler{}, WithHandleFiltering(), WithPanicRecovery(func(r any) {
+		recovered = append(recovered, r)
+	}))
+	logger := slog.New(handler)
+
+	logger.Debug("dropped by underlying handler")
+	logger.Info("forwarded by underlying handler")
+
+	// Enabled and Handle each recover once for the forwarded record
+	if len(recovered) != 3 || recovered[0] != "remote config unavailable" {
+		t.Fatalf("recovered %v, want 3 panics", recovered)
+	}
+	assertHandler.AssertMessage("forwarded by underlying handler")
+}
+
+// TestPanicWithoutRecovery verifies that panics propagate without the option
+func TestPanicWithoutRecovery(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, panickingLeveler{})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Enabled should panic without WithPanicRecovery")
+		}
+	}()
+	handler.Enabled(t.Context(), slog.LevelInfo)
+}
@@ -0,0 +1,168 @@
+package slogleveloverride
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Registry keeps track of named [OverrideHandler]s so that their levels can
+// be managed by name, e.g. from configuration or an admin interface.
+//
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]*OverrideHandler
+	unwatch  map[string]func()
+	labels   map[string]map[string]string
+	patterns []patternLevel
+
+	observers atomic.Pointer[[]Observer]
+}
+
+// NewRegistry creates an empty [Registry].
+func NewRegistry() *Registry {
+	return &Registry{
+		handlers: make(map[string]*OverrideHandler),
+		unwatch:  make(map[string]func()),
+		labels:   make(map[string]map[string]string),
+	}
+}
+
+// Register adds h to the registry under name, replacing any handler
+// previously registered with the same name. If a pattern set with SetLevel
+// matches name, h gets its level.
+func (r *Registry) Register(name string, h *OverrideHandler) {
+	r.mu.Lock()
+	replaced := r.remove(name)
+	r.handlers[name] = h
+	r.unwatch[name] = r.observe(name, h)
+	if level, ok := r.patternLevel(name); ok && checkPolicy(name, level) == nil {
+		h.storeLevel(SourceCode, level)
+	}
+	r.mu.Unlock()
+
+	if replaced {
+		r.notifyObservers(func(o Observer) { o.OnRemove(name) })
+	}
+	r.notifyObservers(func(o Observer) { o.OnRegister(name, h) })
+}
+
+// Unregister removes the handler registered under name, if any.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	removed := r.remove(name)
+	r.mu.Unlock()
+
+	if removed {
+		r.notifyObservers(func(o Observer) { o.OnRemove(name) })
+	}
+}
+
+// remove removes the handler registered under name and reports whether
+// there was one. r.mu must be held.
+func (r *Registry) remove(name string) bool {
+	if _, ok := r.handlers[name]; !ok {
+		return false
+	}
+	r.unwatch[name]()
+	delete(r.unwatch, name)
+	delete(r.handlers, name)
+	delete(r.labels, name)
+	return true
+}
+
+// Handler returns the handler registered under name.
+func (r *Registry) Handler(name string) (*OverrideHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.handlers[name]
+	return h, ok
+}
+
+// Names returns the sorted names of all registered handlers.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.handlers))
+	for name := range r.handlers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SetLevel sets the level override of the handler registered under name.
+//
+// If name is a pattern in which '*' matches any sequence of characters,
+// such as "db.*", the level is set on every matching handler, and kept for
+// handlers registered later; see [Registry.SetPatternLevel].
+//
+// Returns false if no handler is registered under name, if newLevel is nil
+// or if the change was rejected by the policy set with [SetPolicy].
+func (r *Registry) SetLevel(name string, newLevel slog.Leveler) bool {
+	if strings.Contains(name, "*") {
+		return newLevel != nil && r.SetPatternLevel(name, newLevel) == nil
+	}
+	h, ok := r.Handler(name)
+	if !ok || newLevel == nil {
+		return false
+	}
+	return h.setLevel(name, SourceCode, newLevel) == nil
+}
+
+// Apply sets the level overrides of many registered handlers at once, e.g.
+// from an incident runbook, and returns a function undoing the changes.
+//
+// The changes are validated first: if a name is not registered, a level is
+// nil or a change is rejected by the policy set with [SetPolicy], an error
+// is returned and no level is changed. Concurrent calls to
+// Apply and ApplySpec do not interleave. Changes are made in the order of
+// the names, so that the last name wins among handlers sharing their
+// override. Calling undo restores the overrides the handlers had before
+// Apply, including clearing them if they had none.
+func (r *Registry) Apply(changes map[string]slog.Leveler) (undo func(), err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.apply(changes)
+}
+
+// apply implements Apply. r.mu must be held for writing.
+func (r *Registry) apply(changes map[string]slog.Leveler) (undo func(), err error) {
+	names := slices.Sorted(maps.Keys(changes))
+	for _, name := range names {
+		if _, ok := r.handlers[name]; !ok {
+			return nil, fmt.Errorf("slogleveloverride: unknown handler %q", name)
+		}
+		if changes[name] == nil {
+			return nil, fmt.Errorf("slogleveloverride: nil level for handler %q", name)
+		}
+		if err := checkPolicy(name, changes[name]); err != nil {
+			return nil, err
+		}
+	}
+	// Handlers sharing their override, such as a handler and one derived
+	// from it, are restored once, to the override they had before the
+	// first change.
+	restores := make([]func(), 0, len(changes))
+	saved := make(map[*levelState]bool, len(changes))
+	for _, name := range names {
+		h := r.handlers[name]
+		if !saved[h.state] {
+			saved[h.state] = true
+			restores = append(restores, restoreFunc(h))
+		}
+		h.storeLevel(SourceCode, changes[name])
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, restore := range slices.Backward(restores) {
+			restore()
+		}
+	}, nil
+}
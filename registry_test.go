@@ -0,0 +1,108 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+// TestRegistry verifies registration and level changes by name
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	db := New(slog.DiscardHandler)
+	http := New(slog.DiscardHandler)
+	registry.Register("db", db)
+	registry.Register("http", http)
+
+	if got, want := registry.Names(), []string{"db", "http"}; !slices.Equal(got, want) {
+		t.Fatalf("Names returned %v, want %v", got, want)
+	}
+	if h, ok := registry.Handler("db"); !ok || h != db {
+		t.Fatalf("Handler returned (%v, %v), want (%v, true)", h, ok, db)
+	}
+
+	if !registry.SetLevel("db", slog.LevelDebug) {
+		t.Fatal("SetLevel should return true for a registered name")
+	}
+	if level, ok := db.Level(); !ok || level != slog.LevelDebug {
+		t.Fatalf("db level is (%v, %v), want (%v, true)", level, ok, slog.LevelDebug)
+	}
+	if http.HasOverride() {
+		t.Fatal("http should not have an override")
+	}
+
+	registry.Unregister("db")
+	if registry.SetLevel("db", slog.LevelInfo) {
+		t.Fatal("SetLevel should return false for an unregistered name")
+	}
+}
+
+// TestRegistryApply verifies all-or-nothing bulk changes and their undo
+func TestRegistryApply(t *testing.T) {
+	registry := NewRegistry()
+	db := NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	http := New(slog.DiscardHandler)
+	registry.Register("db", db)
+	registry.Register("http", http)
+
+	if _, err := registry.Apply(map[string]slog.Leveler{
+		"db":    slog.LevelDebug,
+		"cache": slog.LevelDebug,
+	}); err == nil {
+		t.Fatal("Apply should fail for an unknown handler")
+	}
+	if _, err := registry.Apply(map[string]slog.Leveler{"http": nil}); err == nil {
+		t.Fatal("Apply should fail for a nil level")
+	}
+	if level, _ := db.Level(); level != slog.LevelWarn {
+		t.Fatalf("db level is %v after a failed Apply, want %v", level, slog.LevelWarn)
+	}
+
+	undo, err := registry.Apply(map[string]slog.Leveler{
+		"db":   slog.LevelDebug,
+		"http": slog.LevelError,
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if level, _ := db.Level(); level != slog.LevelDebug {
+		t.Fatalf("db level is %v, want %v", level, slog.LevelDebug)
+	}
+	if level, _ := http.Level(); level != slog.LevelError {
+		t.Fatalf("http level is %v, want %v", level, slog.LevelError)
+	}
+
+	undo()
+	if level, _ := db.Level(); level != slog.LevelWarn {
+		t.Fatalf("db level is %v after undo, want %v", level, slog.LevelWarn)
+	}
+	if http.HasOverride() {
+		t.Fatal("http should have no override after undo")
+	}
+}
+
+// TestRegistryApplySharedState verifies that undo restores handlers sharing their override to the level before Apply
+func TestRegistryApplySharedState(t *testing.T) {
+	parent := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	child := parent.WithAttrs([]slog.Attr{slog.String("component", "db")}).(*OverrideHandler)
+	registry := NewRegistry()
+	registry.Register("app", parent)
+	registry.Register("app.db", child)
+
+	for range 20 {
+		undo, err := registry.Apply(map[string]slog.Leveler{
+			"app":    slog.LevelDebug,
+			"app.db": slog.LevelWarn,
+		})
+		if err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+		if level, _ := child.Level(); level != slog.LevelWarn {
+			t.Fatalf("shared level is %v, want the last change in name order, %v", level, slog.LevelWarn)
+		}
+		undo()
+		if level, _ := parent.Level(); level != slog.LevelInfo {
+			t.Fatalf("shared level is %v after undo, want %v", level, slog.LevelInfo)
+		}
+	}
+}
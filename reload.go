@@ -0,0 +1,78 @@
+//go:build !js
+
+package slogleveloverride
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ReloadOnHangup sets the override of h from the environment variable
+// [LevelEnvVar] and, if file is not empty, from the level written in file,
+// which takes precedence. Both are read again whenever the process receives
+// SIGHUP, covering the pattern of configuring through the environment and
+// reloading on a signal in one call.
+//
+// Levels are parsed like [slog.Level.UnmarshalText]; surrounding whitespace
+// is ignored. If neither provides a level, the override is left unchanged.
+// Errors reading the level at startup are returned; errors on reload are
+// sent as a Warn record to the underlying handler, keeping the previous
+// level. Calling stop ends the signal handling.
+func ReloadOnHangup(h *OverrideHandler, file string) (stop func(), err error) {
+	if err := reloadLevel(h, file, "env:"+LevelEnvVar); err != nil {
+		return nil, err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-signals:
+				if err := reloadLevel(h, file, SourceSignal); err != nil {
+					r := slog.NewRecord(time.Now(), slog.LevelWarn, "log level reload failed", 0)
+					r.AddAttrs(slog.String("error", err.Error()))
+					_ = h.wrapped().Handle(context.Background(), r)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}, nil
+}
+
+// reloadLevel sets the override of h from the environment and file. A level
+// from the environment is recorded as changed by envSource, one from file
+// by [FileSource].
+func reloadLevel(h *OverrideHandler, file, envSource string) error {
+	text := strings.TrimSpace(os.Getenv(LevelEnvVar))
+	origin, source := "$"+LevelEnvVar, envSource
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("slogleveloverride: %w", err)
+		}
+		if s := strings.TrimSpace(string(content)); s != "" {
+			text, origin, source = s, file, FileSource(file)
+		}
+	}
+	if text == "" {
+		return nil
+	}
+	level, err := parseLevel(text)
+	if err != nil {
+		return fmt.Errorf("slogleveloverride: invalid level in %s: %w", origin, err)
+	}
+	return h.setLevel("", source, level)
+}
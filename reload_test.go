@@ -0,0 +1,56 @@
+//go:build !windows && !plan9 && !js && !wasip1
+
+package slogleveloverride
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestReloadOnHangup verifies the startup level and the reload on SIGHUP
+func TestReloadOnHangup(t *testing.T) {
+	t.Setenv(LevelEnvVar, "warn")
+	file := filepath.Join(t.TempDir(), "level")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := New(slog.DiscardHandler)
+	stop, err := ReloadOnHangup(handler, file)
+	if err != nil {
+		t.Fatalf("ReloadOnHangup failed: %v", err)
+	}
+	defer stop()
+	if level, ok := handler.Level(); !ok || level != slog.LevelWarn {
+		t.Fatalf("level is (%v, %v) at startup, want (%v, true)", level, ok, slog.LevelWarn)
+	}
+
+	if err := os.WriteFile(file, []byte("debug\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Skipf("cannot send SIGHUP: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if level, _ := handler.Level(); level == slog.LevelDebug {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("level was not reloaded after SIGHUP")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestReloadOnHangupInvalid verifies that an invalid startup level is reported
+func TestReloadOnHangupInvalid(t *testing.T) {
+	t.Setenv(LevelEnvVar, "loud")
+	if _, err := ReloadOnHangup(New(slog.DiscardHandler), ""); err == nil {
+		t.Fatal("ReloadOnHangup should fail for an invalid level")
+	}
+}
@@ -0,0 +1,72 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"runtime"
+)
+
+// RemapRule rewrites the level of matching records before they are
+// forwarded, e.g. to demote a noisy library's errors to warnings or to
+// promote a specific warning to an error.
+//
+// A record matches if its level is From, its message equals Message unless
+// Message is empty, and its source matches File or Function as described for
+// [SourceRule] unless both are empty.
+type RemapRule struct {
+	From     slog.Level
+	Message  string
+	File     string
+	Function string
+	To       slog.Level
+}
+
+func (r RemapRule) matches(record slog.Record, frame func() runtime.Frame) bool {
+	if record.Level != r.From || (r.Message != "" && record.Message != r.Message) {
+		return false
+	}
+	if r.File == "" && r.Function == "" {
+		return true
+	}
+	return SourceRule{File: r.File, Function: r.Function}.matches(frame())
+}
+
+// SetRemapRules replaces the level remapping rules of this handler and of
+// every handler sharing its configuration. Calling it without rules removes
+// them.
+//
+// Rules are evaluated in Handle, and the first matching rule applies. A
+// remapped record must still pass the threshold at its new level. Records
+// are only seen by Handle if Enabled accepted their original level, so a
+// record cannot be promoted out of a level the threshold rejects.
+func (h *OverrideHandler) SetRemapRules(rules ...RemapRule) {
+	if len(rules) == 0 {
+		h.opts.remapRules.Store(nil)
+		return
+	}
+	rs := append([]RemapRule(nil), rules...)
+	h.opts.remapRules.Store(&rs)
+}
+
+// remap returns record with the level given by the first matching
+// [RemapRule], and whether a rule matched.
+func (h *OverrideHandler) remap(record slog.Record) (slog.Record, bool) {
+	rs := h.opts.remapRules.Load()
+	if rs == nil {
+		return record, false
+	}
+	var resolved *runtime.Frame
+	frame := func() runtime.Frame {
+		if resolved == nil {
+			f, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+			resolved = &f
+		}
+		return *resolved
+	}
+	for _, r := range *rs {
+		if r.matches(record, frame) {
+			record.Level = r.To
+			return record, true
+		}
+	}
+	return record, false
+}
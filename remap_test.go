@@ -0,0 +1,36 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestSetRemapRules verifies that record levels are rewritten before forwarding
+func TestSetRemapRules(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelInfo)
+	handler.SetRemapRules(
+		RemapRule{From: slog.LevelError, Function: "*.TestSetRemapRules", To: slog.LevelWarn},
+		RemapRule{From: slog.LevelWarn, Message: "disk full", To: slog.LevelError},
+		RemapRule{From: slog.LevelInfo, Message: "heartbeat", To: slog.LevelDebug},
+	)
+	logger := slog.New(handler)
+
+	logger.Error("noisy error")
+	logger.Warn("disk full")
+	logger.Warn("other warning")
+	// Remapped below the threshold, so dropped
+	logger.Info("heartbeat")
+
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{Message: "noisy error", Level: slog.LevelWarn})
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{Message: "disk full", Level: slog.LevelError})
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{Message: "other warning", Level: slog.LevelWarn})
+
+	handler.SetRemapRules()
+	logger.Error("plain error")
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{Message: "plain error", Level: slog.LevelError})
+}
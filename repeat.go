@@ -0,0 +1,157 @@
+package slogleveloverride
+
+import (
+	"context"
+	"hash/maphash"
+	"log/slog"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RepeatCountKey is the attribute key of the number of occurrences added by
+// [WithRepeatBackoff].
+const RepeatCountKey = "repeat_count"
+
+// repeatBackoff tracks the occurrences of repeated records.
+type repeatBackoff struct {
+	quiet   time.Duration
+	maxKeys int
+	seed    maphash.Seed
+	enabled atomic.Bool
+
+	mu        sync.Mutex
+	repeats   map[uint64]*repeat
+	lastSweep time.Time
+}
+
+// repeat is the occurrences of a record since the last quiet period.
+type repeat struct {
+	count uint64
+	last  time.Time
+}
+
+// WithRepeatBackoff emits only the 1st, 2nd, 4th, 8th... occurrence of
+// repeated records, with the number of occurrences so far as attribute
+// [RepeatCountKey], so that a message logged in a tight loop cannot flood
+// the output while its rate stays visible. Records repeat when they have
+// the same level, message and attributes, including those added with
+// WithAttrs. The count of a record resets once it has not occurred for
+// quiet.
+//
+// Up to maxKeys distinct records are tracked; beyond that, records not yet
+// tracked are emitted as they are. Other occurrences are treated as
+// suppressed records, feeding [WithSuppressedDigest] and the like. The
+// backoff can be turned off and on at runtime with
+// [OverrideHandler.SetRepeatBackoff].
+func WithRepeatBackoff(quiet time.Duration, maxKeys int) Option {
+	return func(o *options) {
+		b := &repeatBackoff{
+			quiet:   quiet,
+			maxKeys: max(maxKeys, 1),
+			seed:    maphash.MakeSeed(),
+			repeats: make(map[uint64]*repeat),
+		}
+		b.enabled.Store(true)
+		o.repeatBackoff = b
+	}
+}
+
+// SetRepeatBackoff turns the backoff of [WithRepeatBackoff] on or off for
+// this handler and every handler sharing its configuration. Turning it off
+// forgets the occurrences counted so far.
+func (h *OverrideHandler) SetRepeatBackoff(on bool) {
+	b := h.opts.repeatBackoff
+	if b == nil {
+		return
+	}
+	b.enabled.Store(on)
+	if !on {
+		b.mu.Lock()
+		clear(b.repeats)
+		b.mu.Unlock()
+	}
+}
+
+// occurrence counts an occurrence of the record hashed to key and returns
+// the count, or 0 if the record is not tracked.
+func (b *repeatBackoff) occurrence(key uint64, now time.Time) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r, ok := b.repeats[key]
+	if !ok {
+		if len(b.repeats) >= b.maxKeys {
+			b.sweep(now)
+			if len(b.repeats) >= b.maxKeys {
+				return 0
+			}
+		}
+		r = &repeat{}
+		b.repeats[key] = r
+	}
+	if now.Sub(r.last) >= b.quiet {
+		r.count = 0
+	}
+	r.count++
+	r.last = now
+	return r.count
+}
+
+// sweep removes the records that have been quiet for long enough, at most
+// once per half quiet period so that a full backoff does not scan its
+// records for every new one.
+func (b *repeatBackoff) sweep(now time.Time) {
+	if now.Sub(b.lastSweep) < b.quiet/2 {
+		return
+	}
+	b.lastSweep = now
+	for key, r := range b.repeats {
+		if now.Sub(r.last) >= b.quiet {
+			delete(b.repeats, key)
+		}
+	}
+}
+
+// hash returns the hash of the level, message and attributes of record and
+// of the attributes attrs of the handler that received it.
+func (b *repeatBackoff) hash(record slog.Record, attrs []slog.Attr) uint64 {
+	var m maphash.Hash
+	m.SetSeed(b.seed)
+	m.WriteString(record.Level.String())
+	m.WriteByte(0)
+	m.WriteString(record.Message)
+	write := func(a slog.Attr) bool {
+		m.WriteByte(0)
+		m.WriteString(a.Key)
+		m.WriteByte('=')
+		m.WriteString(a.Value.String())
+		return true
+	}
+	for _, a := range attrs {
+		write(a)
+	}
+	record.Attrs(write)
+	return m.Sum64()
+}
+
+// repeated reports whether record is an occurrence of a repeated record to
+// suppress, and otherwise returns record with its count added if it is
+// tracked.
+func (h *OverrideHandler) repeated(ctx context.Context, record slog.Record) (slog.Record, bool) {
+	b := h.opts.repeatBackoff
+	if b == nil || !b.enabled.Load() {
+		return record, false
+	}
+	count := b.occurrence(b.hash(record, h.attrs), time.Now())
+	if count == 0 {
+		return record, false
+	}
+	if bits.OnesCount64(count) != 1 {
+		h.handleSuppressed(ctx, record)
+		return record, true
+	}
+	record = record.Clone()
+	record.AddAttrs(slog.Uint64(RepeatCountKey, count))
+	return record, false
+}
@@ -0,0 +1,86 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRepeatBackoff verifies that repeats are emitted at powers of two with their count
+func TestRepeatBackoff(t *testing.T) {
+	var out bytes.Buffer
+	handler := NewTextHandler(&out, nil, WithRepeatBackoff(time.Hour, 16))
+	logger := slog.New(handler).With("component", "db")
+
+	for range 9 {
+		logger.Warn("connection refused", "host", "a")
+	}
+	logger.Warn("connection refused", "host", "b")
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		_, rest, _ := strings.Cut(line, "msg=")
+		got = append(got, rest)
+	}
+	want := []string{
+		`"connection refused" component=db host=a repeat_count=1`,
+		`"connection refused" component=db host=a repeat_count=2`,
+		`"connection refused" component=db host=a repeat_count=4`,
+		`"connection refused" component=db host=a repeat_count=8`,
+		`"connection refused" component=db host=b repeat_count=1`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestRepeatBackoffQuiet verifies that counts reset after a quiet period and that the backoff can be turned off
+func TestRepeatBackoffQuiet(t *testing.T) {
+	var out bytes.Buffer
+	handler := NewTextHandler(&out, nil, WithRepeatBackoff(20*time.Millisecond, 16))
+	logger := slog.New(handler)
+
+	logger.Info("tick")
+	logger.Info("tick")
+	logger.Info("tick")
+	time.Sleep(30 * time.Millisecond)
+	logger.Info("tick")
+	if got := strings.Count(out.String(), "repeat_count=1"); got != 2 {
+		t.Errorf("output %q, want the count reset once", out.String())
+	}
+
+	out.Reset()
+	handler.SetRepeatBackoff(false)
+	for range 3 {
+		logger.Info("tick")
+	}
+	if got := strings.Count(out.String(), "msg=tick\n"); got != 3 {
+		t.Errorf("output %q with the backoff off", out.String())
+	}
+}
+
+// TestRepeatBackoffFull verifies that a full backoff emits new records untracked and sweeps at most once per half quiet period
+func TestRepeatBackoffFull(t *testing.T) {
+	b := &repeatBackoff{quiet: time.Second, maxKeys: 2, repeats: make(map[uint64]*repeat)}
+	now := time.Now()
+
+	b.occurrence(1, now)
+	b.occurrence(2, now)
+	if got := b.occurrence(3, now.Add(100*time.Millisecond)); got != 0 {
+		t.Fatalf("occurrence of an untracked record = %d, want 0", got)
+	}
+	if got := b.occurrence(3, now.Add(200*time.Millisecond)); got != 0 {
+		t.Fatalf("occurrence of an untracked record = %d, want 0", got)
+	}
+	if want := now.Add(100 * time.Millisecond); !b.lastSweep.Equal(want) {
+		t.Errorf("last sweep at %v, want %v", b.lastSweep, want)
+	}
+	if got := b.occurrence(3, now.Add(1200*time.Millisecond)); got != 1 {
+		t.Errorf("occurrence = %d after the other records became quiet, want 1", got)
+	}
+	if len(b.repeats) != 1 {
+		t.Errorf("backoff tracks %d records, want 1", len(b.repeats))
+	}
+}
@@ -0,0 +1,93 @@
+package slogleveloverride
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// Route is a destination of a [Router] for a range of levels.
+type Route struct {
+	// Handler receives the records with a level in the range of the route.
+	Handler slog.Handler
+
+	// Min is the lowest level routed to Handler. If nil, there is no lower
+	// bound.
+	Min slog.Leveler
+
+	// Below is the level from which records are no longer routed to
+	// Handler. If nil, there is no upper bound.
+	Below slog.Leveler
+}
+
+// contains reports whether level is in the range of r.
+func (r Route) contains(level slog.Level) bool {
+	return (r.Min == nil || level >= r.Min.Level()) && (r.Below == nil || level < r.Below.Level())
+}
+
+// Router is a [slog.Handler] sending each record to the routes whose range
+// contains its level, e.g. Error and above to stderr and a pager, the rest
+// to stdout. The bounds are [slog.Leveler] values read for every record, so
+// that a [*slog.LevelVar] shared as the Min of one route and the Below of
+// another moves the split point at runtime.
+//
+// Wrap a Router with [New] to also filter records by a dynamic level.
+type Router struct {
+	routes []Route
+}
+
+// NewRouter returns a Router sending records to routes.
+func NewRouter(routes ...Route) *Router {
+	return &Router{routes: routes}
+}
+
+// Enabled reports whether a route containing level has a handler enabled
+// for it.
+func (r *Router) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, route := range r.routes {
+		if route.contains(level) && route.Handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle sends record to every route containing its level and returns the
+// errors of their handlers, joined.
+func (r *Router) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, route := range r.routes {
+		if !route.contains(record.Level) || !route.Handler.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := route.Handler.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a Router whose route handlers have attrs added.
+func (r *Router) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return r.derive(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+// WithGroup returns a Router whose route handlers have the group name
+// added.
+func (r *Router) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return r
+	}
+	return r.derive(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+// derive returns a Router with derivation applied to every route handler,
+// keeping the bounds of the routes.
+func (r *Router) derive(derivation func(slog.Handler) slog.Handler) *Router {
+	routes := make([]Route, len(r.routes))
+	for i, route := range r.routes {
+		route.Handler = derivation(route.Handler)
+		routes[i] = route
+	}
+	return &Router{routes: routes}
+}
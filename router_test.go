@@ -0,0 +1,62 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestRouter verifies that records go to the routes containing their level
+// and that a shared threshold moves the split point
+func TestRouter(t *testing.T) {
+	var stdout, stderr, pager bytes.Buffer
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	split := new(slog.LevelVar)
+	split.Set(slog.LevelError)
+	router := NewRouter(
+		Route{Handler: slog.NewTextHandler(&stdout, opts), Below: split},
+		Route{Handler: slog.NewTextHandler(&stderr, opts), Min: split},
+		Route{Handler: slog.NewTextHandler(&pager, opts), Min: split},
+	)
+	handler := NewWithLevel(router, slog.LevelInfo)
+	logger := slog.New(handler).With("component", "db")
+
+	logger.Debug("filtered")
+	logger.Warn("minor")
+	logger.Error("major")
+
+	if got := stdout.String(); !strings.Contains(got, "msg=minor component=db") || strings.Contains(got, "major") || strings.Contains(got, "filtered") {
+		t.Errorf("stdout got %q", got)
+	}
+	for name, out := range map[string]*bytes.Buffer{"stderr": &stderr, "pager": &pager} {
+		if got := out.String(); !strings.Contains(got, "msg=major component=db") || strings.Contains(got, "minor") {
+			t.Errorf("%s got %q", name, got)
+		}
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	split.Set(slog.LevelWarn)
+	handler.SetLevel(slog.LevelDebug)
+	logger.Debug("detail")
+	logger.Warn("minor")
+	if got := stdout.String(); !strings.Contains(got, "detail") || strings.Contains(got, "minor") {
+		t.Errorf("stdout got %q after moving the split", got)
+	}
+	if !strings.Contains(stderr.String(), "minor") {
+		t.Errorf("stderr got %q after moving the split", stderr.String())
+	}
+}
+
+// TestRouterEnabled verifies that Enabled accounts for the route handlers
+func TestRouterEnabled(t *testing.T) {
+	router := NewRouter(
+		Route{Handler: slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn}), Below: slog.LevelError},
+	)
+	for level, want := range map[slog.Level]bool{slog.LevelInfo: false, slog.LevelWarn: true, slog.LevelError: false} {
+		if got := router.Enabled(t.Context(), level); got != want {
+			t.Errorf("Enabled(%v) = %v, want %v", level, got, want)
+		}
+	}
+}
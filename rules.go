@@ -0,0 +1,300 @@
+package slogleveloverride
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Rule is a structured override rule letting matching records through at a
+// more verbose level than the regular threshold, possibly sampled. Rules are
+// written in a small language parsed by [ParseRules], e.g.
+//
+//	scope=db level>=debug attr.tenant=acme sample=0.1
+//
+// which forwards one in ten records of level Debug or above carrying the
+// attribute tenant=acme from the handler registered as "db". Rules can also
+// be decoded from JSON, e.g.
+//
+//	{"scope": "db", "level": "DEBUG", "attrs": {"tenant": "acme"}, "sample": 0.1}
+//
+// Adding sample_by=trace_id samples by the hash of the trace_id attribute
+// rather than at random, so that either all or none of the records of a
+// trace are forwarded.
+type Rule struct {
+	// Scope restricts the rule to the registry names it matches, with '*'
+	// matching any sequence of characters. It is only used by
+	// [Registry.SetRules]; an empty scope matches every name.
+	Scope string `json:"scope,omitempty"`
+	// Level is the threshold of matching records.
+	Level slog.Level `json:"level"`
+	// Attrs are the top-level attributes a record must carry, by key. Values
+	// are compared with the string form of the attribute value, and '*'
+	// matches any sequence of characters.
+	Attrs map[string]string `json:"attrs,omitempty"`
+	// Sample is the fraction of matching records at or above Level that are
+	// forwarded, among those the regular threshold rejects; records it
+	// accepts are always forwarded. Zero forwards all of them.
+	Sample float64 `json:"sample,omitempty"`
+	// SampleBy, if not empty, is the key of the attribute whose value
+	// decides whether a record is sampled, through a hash that is the same
+	// in every process, so that records sharing the value are all forwarded
+	// or all dropped. Records without the attribute are sampled at random.
+	SampleBy string `json:"sample_by,omitempty"`
+}
+
+// ParseRules parses rules separated by newlines or semicolons. A rule is a
+// whitespace-separated list of the terms scope=PATTERN, level>=LEVEL,
+// attr.KEY=PATTERN, sample=FRACTION and sample_by=KEY, of which level>= is
+// required. Levels
+// are parsed like in [ParseSpec]. Empty rules and lines starting with '#'
+// are ignored.
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+	for line := range strings.SplitSeq(s, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for text := range strings.SplitSeq(line, ";") {
+			terms := strings.Fields(text)
+			if len(terms) == 0 {
+				continue
+			}
+			r, err := parseRule(terms)
+			if err != nil {
+				return nil, fmt.Errorf("slogleveloverride: invalid rule %q: %w", strings.TrimSpace(text), err)
+			}
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+// parseRule parses the terms of a single rule.
+func parseRule(terms []string) (Rule, error) {
+	var r Rule
+	hasLevel := false
+	for _, term := range terms {
+		if levelText, ok := strings.CutPrefix(term, "level>="); ok {
+			level, err := parseLevel(levelText)
+			if err != nil {
+				return Rule{}, err
+			}
+			r.Level, hasLevel = level, true
+			continue
+		}
+		key, value, found := strings.Cut(term, "=")
+		if !found {
+			return Rule{}, fmt.Errorf("term %q is not of the form key=value", term)
+		}
+		switch attr, isAttr := strings.CutPrefix(key, "attr."); {
+		case key == "scope":
+			r.Scope = value
+		case key == "sample":
+			sample, err := strconv.ParseFloat(value, 64)
+			if err != nil || !validSample(sample) {
+				return Rule{}, fmt.Errorf("sample %q is not a fraction in (0, 1]", value)
+			}
+			r.Sample = sample
+		case key == "sample_by":
+			r.SampleBy = value
+		case isAttr && attr != "":
+			if r.Attrs == nil {
+				r.Attrs = make(map[string]string)
+			}
+			r.Attrs[attr] = value
+		default:
+			return Rule{}, fmt.Errorf("unknown term %q", term)
+		}
+	}
+	if !hasLevel {
+		return Rule{}, fmt.Errorf("missing level>=")
+	}
+	return r, nil
+}
+
+// validSample reports whether sample is a fraction in (0, 1].
+func validSample(sample float64) bool {
+	return sample > 0 && sample <= 1
+}
+
+// UnmarshalJSON decodes a rule from JSON, rejecting a sample that is not a
+// fraction in (0, 1] like [ParseRules] does. An omitted sample is accepted.
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	// rule has the fields of Rule without its methods.
+	type rule Rule
+	var decoded rule
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Sample != 0 && !validSample(decoded.Sample) {
+		return fmt.Errorf("slogleveloverride: sample %v is not a fraction in (0, 1]", decoded.Sample)
+	}
+	*r = Rule(decoded)
+	return nil
+}
+
+// String formats the rule in the syntax accepted by [ParseRules].
+func (r Rule) String() string {
+	var b strings.Builder
+	if r.Scope != "" {
+		b.WriteString("scope=" + r.Scope + " ")
+	}
+	b.WriteString("level>=" + r.Level.String())
+	for _, key := range slices.Sorted(maps.Keys(r.Attrs)) {
+		b.WriteString(" attr." + key + "=" + r.Attrs[key])
+	}
+	if r.Sample != 0 {
+		b.WriteString(" sample=" + strconv.FormatFloat(r.Sample, 'g', -1, 64))
+	}
+	if r.SampleBy != "" {
+		b.WriteString(" sample_by=" + r.SampleBy)
+	}
+	return b.String()
+}
+
+// attrMatcher matches the value of a single attribute.
+type attrMatcher struct {
+	key     string
+	pattern string
+	glob    bool
+}
+
+// compiledRule is a [Rule] prepared for evaluation in Handle.
+type compiledRule struct {
+	level    slog.Level
+	attrs    []attrMatcher
+	sample   float64
+	sampleBy string
+}
+
+// compiledRules is an immutable set of compiled rules.
+type compiledRules struct {
+	rules    []compiledRule
+	minLevel slog.Level
+}
+
+// compileRules compiles rules, or returns nil if there are none.
+func compileRules(rules []Rule) *compiledRules {
+	if len(rules) == 0 {
+		return nil
+	}
+	cs := &compiledRules{minLevel: rules[0].Level}
+	for _, r := range rules {
+		c := compiledRule{level: r.Level, sample: r.Sample, sampleBy: r.SampleBy}
+		for _, key := range slices.Sorted(maps.Keys(r.Attrs)) {
+			pattern := r.Attrs[key]
+			c.attrs = append(c.attrs, attrMatcher{key: key, pattern: pattern, glob: strings.Contains(pattern, "*")})
+		}
+		cs.rules = append(cs.rules, c)
+		cs.minLevel = min(cs.minLevel, r.Level)
+	}
+	return cs
+}
+
+// matches reports whether the attributes of record satisfy the rule.
+func (c compiledRule) matches(h *OverrideHandler, record slog.Record) bool {
+	for _, m := range c.attrs {
+		value, ok := h.lookupAttr(record, m.key)
+		if !ok {
+			return false
+		}
+		s := value.Resolve().String()
+		if m.glob && !globMatch(m.pattern, s) || !m.glob && s != m.pattern {
+			return false
+		}
+	}
+	return true
+}
+
+// allows reports whether record, received by h and matching the rule, is
+// forwarded. Only records rejected by the regular threshold are sampled.
+func (c compiledRule) allows(ctx context.Context, h *OverrideHandler, record slog.Record) bool {
+	if record.Level < c.level || c.sample == 0 {
+		return record.Level >= c.level
+	}
+	if h.enabled(ctx, record.Level) {
+		return true
+	}
+	if c.sampleBy != "" {
+		if value, ok := h.lookupAttr(record, c.sampleBy); ok {
+			return sampleFraction(value.Resolve().String()) < c.sample
+		}
+	}
+	return rand.Float64() < c.sample
+}
+
+// sampleFraction maps value to a fraction in [0, 1) through a hash that
+// does not depend on the process.
+func sampleFraction(value string) float64 {
+	f := fnv.New64a()
+	f.Write([]byte(value))
+	// FNV barely changes the high bits for values differing in their last
+	// bytes, such as sequential IDs; mix them in as SplitMix64 does.
+	x := f.Sum64()
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11) / (1 << 53)
+}
+
+// SetRules replaces the override rules of this handler and of every handler
+// sharing its configuration. The Scope of the rules is ignored. Calling it
+// without rules removes them.
+//
+// The first rule whose attributes match a record decides whether it is
+// forwarded, instead of the regular threshold, except that a sampled rule
+// only samples records the threshold rejects and forwards the others. Since attributes are only
+// known in Handle, Enabled reports true for any level that some rule could
+// accept, and the final decision is made in Handle.
+func (h *OverrideHandler) SetRules(rules ...Rule) {
+	if len(rules) > 0 {
+		h.opts.slow.Store(true)
+	}
+	h.opts.rules.Store(compileRules(rules))
+}
+
+// SetRules sets the override rules of every registered handler to the rules
+// whose scope matches its name, as with [OverrideHandler.SetRules]. Handlers
+// registered later are not affected.
+func (r *Registry) SetRules(rules ...Rule) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name, h := range r.handlers {
+		var matched []Rule
+		for _, rule := range rules {
+			if rule.Scope == "" || globMatch(rule.Scope, name) {
+				matched = append(matched, rule)
+			}
+		}
+		h.SetRules(matched...)
+	}
+}
+
+// ruleEnabled reports whether a record at level may pass due to a [Rule]
+// even though the regular threshold rejects it.
+func (h *OverrideHandler) ruleEnabled(level slog.Level) bool {
+	cs := h.opts.rules.Load()
+	return cs != nil && level >= cs.minLevel
+}
+
+// matchRule returns the first rule matching record.
+func (h *OverrideHandler) matchRule(record slog.Record) (compiledRule, bool) {
+	cs := h.opts.rules.Load()
+	if cs == nil {
+		return compiledRule{}, false
+	}
+	for _, c := range cs.rules {
+		if c.matches(h, record) {
+			return c, true
+		}
+	}
+	return compiledRule{}, false
+}
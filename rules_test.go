@@ -0,0 +1,170 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestParseRules verifies the rule language and its round trip through String
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("# debug for acme\nscope=db level>=debug attr.tenant=acme sample=0.1; level>=warn attr.user=*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Rule{
+		{Scope: "db", Level: slog.LevelDebug, Attrs: map[string]string{"tenant": "acme"}, Sample: 0.1},
+		{Level: slog.LevelWarn, Attrs: map[string]string{"user": "*"}},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("ParseRules = %+v, want %+v", rules, want)
+	}
+	if got := rules[0].String(); got != "scope=db level>=DEBUG attr.tenant=acme sample=0.1" {
+		t.Errorf("String = %q", got)
+	}
+
+	var decoded Rule
+	if err := json.Unmarshal([]byte(`{"scope":"db","level":"DEBUG","attrs":{"tenant":"acme"},"sample":0.1}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, want[0]) {
+		t.Errorf("decoded %+v, want %+v", decoded, want[0])
+	}
+
+	for _, doc := range []string{`{"level":"DEBUG","sample":2}`, `{"level":"DEBUG","sample":-0.5}`} {
+		if err := json.Unmarshal([]byte(doc), &decoded); err == nil {
+			t.Errorf("decoding %s succeeded", doc)
+		}
+	}
+
+	for _, s := range []string{"scope=db", "level>=loud", "level>=info sample=2", "level>=info tenant"} {
+		if _, err := ParseRules(s); err == nil || !strings.HasPrefix(err.Error(), "slogleveloverride: ") {
+			t.Errorf("ParseRules(%q) returned %v", s, err)
+		}
+	}
+}
+
+// TestRules verifies that the first rule matching a record's attributes replaces the threshold
+func TestRules(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelWarn)
+	rules, err := ParseRules("level>=debug attr.tenant=acme; level>=error attr.tenant=noisy*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.SetRules(rules...)
+	logger := slog.New(handler)
+
+	logger.Debug("acme debug", "tenant", "acme")
+	logger.With("tenant", "acme").Info("acme info")
+	logger.Debug("other debug", "tenant", "other")
+	logger.Warn("noisy warn", "tenant", "noisy-co")
+	logger.Warn("other warn", "tenant", "other")
+
+	handler.SetRules()
+	logger.Debug("acme debug after removal", "tenant", "acme")
+
+	assertHandler.AssertMessage("acme debug")
+	assertHandler.AssertMessage("acme info")
+	assertHandler.AssertMessage("other warn")
+}
+
+// TestRulesSample verifies that sampled rules forward only a fraction of matching records
+func TestRulesSample(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+
+	handler := NewWithLevel(assertHandler, slog.LevelWarn)
+	handler.SetRules(Rule{Level: slog.LevelDebug, Sample: 0.1})
+	logger := slog.New(handler)
+
+	for range 1000 {
+		logger.Debug("sampled")
+	}
+	if count := assertHandler.AssertSomeMessage("sampled"); count < 50 || count > 200 {
+		t.Errorf("forwarded %d of 1000 records, want about 100", count)
+	}
+}
+
+// TestRulesSampleAboveThreshold verifies that sampled rules forward every record the threshold accepts
+func TestRulesSampleAboveThreshold(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+
+	handler := NewWithLevel(assertHandler, slog.LevelInfo)
+	rules, err := ParseRules("level>=debug attr.tenant=acme sample=0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.SetRules(rules...)
+	logger := slog.New(handler).With("tenant", "acme")
+
+	for range 100 {
+		logger.Error("acme error")
+		logger.Warn("acme warn")
+	}
+	if count := assertHandler.AssertSomeMessage("acme error"); count != 100 {
+		t.Errorf("forwarded %d of 100 errors, want all", count)
+	}
+	if count := assertHandler.AssertSomeMessage("acme warn"); count != 100 {
+		t.Errorf("forwarded %d of 100 warnings, want all", count)
+	}
+}
+
+// TestRulesSampleBy verifies that hash-based sampling keeps all or none of the records sharing a value
+func TestRulesSampleBy(t *testing.T) {
+	rules, err := ParseRules("level>=debug sample=0.25 sample_by=trace_id")
+	if err != nil {
+		t.Fatalf("ParseRules failed: %v", err)
+	}
+	if got := rules[0].String(); got != "level>=DEBUG sample=0.25 sample_by=trace_id" {
+		t.Errorf("String = %q", got)
+	}
+
+	var out bytes.Buffer
+	handler := NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelWarn})
+	handler.SetRules(rules...)
+	logger := slog.New(handler)
+
+	kept := 0
+	for trace := range 400 {
+		traceLogger := logger.With("trace_id", fmt.Sprintf("%032x", trace))
+		before := strings.Count(out.String(), "\n")
+		for range 3 {
+			traceLogger.Debug("step")
+		}
+		switch strings.Count(out.String(), "\n") - before {
+		case 0:
+		case 3:
+			kept++
+		default:
+			t.Fatalf("trace %d was partially sampled", trace)
+		}
+	}
+	if kept < 60 || kept > 140 {
+		t.Errorf("kept %d of 400 traces, want about 100", kept)
+	}
+}
+
+// TestRegistrySetRules verifies that rules are distributed by scope
+func TestRegistrySetRules(t *testing.T) {
+	db := NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	http := NewWithLevel(slog.DiscardHandler, slog.LevelWarn)
+	registry := NewRegistry()
+	registry.Register("db", db)
+	registry.Register("http", http)
+
+	registry.SetRules(Rule{Scope: "d*", Level: slog.LevelDebug})
+	if !db.Enabled(t.Context(), slog.LevelDebug) {
+		t.Error("db should accept debug records for its rule")
+	}
+	if http.Enabled(t.Context(), slog.LevelDebug) {
+		t.Error("http should not be affected by the rule for d*")
+	}
+}
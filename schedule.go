@@ -0,0 +1,162 @@
+package slogleveloverride
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// LayerSchedule is the layer of time-of-day schedules set with
+// [OverrideHandler.SetSchedule]. It is below [LayerBase], so a manual
+// SetLevel takes precedence over the schedule, and ClearLevel hands control
+// back to it.
+const LayerSchedule Layer = -100
+
+// ScheduleRule sets Level during a daily time window, e.g. Warn between
+// "00:00" and "06:00" for quiet hours.
+type ScheduleRule struct {
+	// From and To are times of day formatted as "15:04". The window
+	// includes From and excludes To, and wraps past midnight if To is not
+	// after From.
+	From, To string
+	Level    slog.Level
+}
+
+// clockRule is a ScheduleRule with parsed times of day.
+type clockRule struct {
+	from, to time.Duration
+	level    slog.Level
+}
+
+// schedule runs the rules of SetSchedule for a handler.
+type schedule struct {
+	h     *OverrideHandler
+	loc   *time.Location
+	rules []clockRule
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+}
+
+// SetSchedule sets the override of [LayerSchedule] according to rules,
+// evaluated in loc, or in [time.Local] if loc is nil, for as long as the
+// schedule runs. The first rule whose window contains the current time of
+// day applies; outside of all windows, the layer has no override.
+//
+// The layer is updated at window boundaries by a timer. SetSchedule replaces
+// any schedule previously set on the handler, and stop ends the schedule and
+// clears the layer.
+func (h *OverrideHandler) SetSchedule(loc *time.Location, rules ...ScheduleRule) (stop func(), err error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	s := &schedule{h: h, loc: loc, rules: make([]clockRule, len(rules))}
+	for i, r := range rules {
+		from, err := parseClock(r.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseClock(r.To)
+		if err != nil {
+			return nil, err
+		}
+		s.rules[i] = clockRule{from: from, to: to, level: r.Level}
+	}
+
+	h.state.mu.Lock()
+	previous := h.state.schedule
+	h.state.schedule = s
+	h.state.mu.Unlock()
+	if previous != nil {
+		previous.stop()
+	}
+	s.run()
+	return func() {
+		h.state.mu.Lock()
+		if h.state.schedule == s {
+			h.state.schedule = nil
+		}
+		h.state.mu.Unlock()
+		if s.stop() {
+			h.ClearLayer(LayerSchedule)
+		}
+	}, nil
+}
+
+// run applies the rule of the current time and arms the timer for the next
+// boundary.
+func (s *schedule) run() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	now := time.Now().In(s.loc)
+	if level, ok := s.level(now); ok {
+		s.h.setLayerLevel(LayerSchedule, "schedule", level)
+	} else {
+		s.h.ClearLayer(LayerSchedule)
+	}
+	s.timer = time.AfterFunc(s.next(now).Sub(now), s.run)
+}
+
+// stop stops the schedule and reports whether it was running.
+func (s *schedule) stop() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return false
+	}
+	s.stopped = true
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	return true
+}
+
+// level returns the level of the first rule whose window contains t.
+func (s *schedule) level(t time.Time) (slog.Level, bool) {
+	clock := sinceMidnight(t)
+	for _, r := range s.rules {
+		if r.from < r.to && clock >= r.from && clock < r.to ||
+			r.from >= r.to && (clock >= r.from || clock < r.to) {
+			return r.level, true
+		}
+	}
+	return 0, false
+}
+
+// next returns the first window boundary after t, or a day later if there
+// are no rules.
+func (s *schedule) next(t time.Time) time.Time {
+	next := t.AddDate(0, 0, 1)
+	for _, r := range s.rules {
+		for _, clock := range []time.Duration{r.from, r.to} {
+			for days := range 2 {
+				y, m, d := t.AddDate(0, 0, days).Date()
+				b := time.Date(y, m, d, int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, t.Location())
+				if b.After(t) && b.Before(next) {
+					next = b
+				}
+			}
+		}
+	}
+	return next
+}
+
+// sinceMidnight returns the time of day of t as an offset from midnight.
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+// parseClock parses a time of day formatted as "15:04".
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("slogleveloverride: invalid time of day %q: %w", s, err)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,76 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestScheduleLevel verifies the rule applying at times of day, including windows past midnight
+func TestScheduleLevel(t *testing.T) {
+	s := &schedule{rules: []clockRule{
+		{from: 22 * time.Hour, to: 6 * time.Hour, level: slog.LevelError},
+		{from: 0, to: 8 * time.Hour, level: slog.LevelWarn},
+		{from: 12 * time.Hour, to: 13 * time.Hour, level: slog.LevelDebug},
+	}}
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		at   time.Duration
+		want slog.Level
+		ok   bool
+	}{
+		{23 * time.Hour, slog.LevelError, true},
+		{2 * time.Hour, slog.LevelError, true},
+		{6 * time.Hour, slog.LevelWarn, true},
+		{8 * time.Hour, 0, false},
+		{12*time.Hour + 30*time.Minute, slog.LevelDebug, true},
+		{13 * time.Hour, 0, false},
+	}
+	for _, tt := range tests {
+		level, ok := s.level(day.Add(tt.at))
+		if level != tt.want || ok != tt.ok {
+			t.Errorf("level at %v = (%v, %v), want (%v, %v)", tt.at, level, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got, want := s.next(day.Add(13*time.Hour)), day.Add(22*time.Hour); !got.Equal(want) {
+		t.Errorf("next boundary after 13:00 is %v, want %v", got, want)
+	}
+	if got, want := s.next(day.Add(23*time.Hour)), day.Add(24*time.Hour); !got.Equal(want) {
+		t.Errorf("next boundary after 23:00 is %v, want %v", got, want)
+	}
+}
+
+// TestSetSchedule verifies the precedence of manual levels over the schedule
+func TestSetSchedule(t *testing.T) {
+	handler := New(slog.DiscardHandler)
+	if _, err := handler.SetSchedule(time.UTC, ScheduleRule{From: "25:00", To: "06:00"}); err == nil {
+		t.Fatal("SetSchedule should fail for an invalid time of day")
+	}
+
+	// Two windows cover the whole day
+	stop, err := handler.SetSchedule(time.UTC,
+		ScheduleRule{From: "00:00", To: "12:00", Level: slog.LevelWarn},
+		ScheduleRule{From: "12:00", To: "00:00", Level: slog.LevelWarn},
+	)
+	if err != nil {
+		t.Fatalf("SetSchedule failed: %v", err)
+	}
+	if level, _ := handler.Level(); level != slog.LevelWarn {
+		t.Fatalf("level is %v with the schedule, want %v", level, slog.LevelWarn)
+	}
+
+	handler.SetLevel(slog.LevelDebug)
+	if level, _ := handler.Level(); level != slog.LevelDebug {
+		t.Fatalf("level is %v after SetLevel, want %v", level, slog.LevelDebug)
+	}
+	handler.ClearLevel()
+	if level, _ := handler.Level(); level != slog.LevelWarn {
+		t.Fatalf("level is %v after ClearLevel, want %v", level, slog.LevelWarn)
+	}
+
+	stop()
+	if handler.HasOverride() {
+		t.Fatal("stopping the schedule should clear its layer")
+	}
+}
@@ -0,0 +1,68 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+)
+
+// replacement is a handler set with [OverrideHandler.SetHandler], numbered
+// by generation, or the wrapped handler of a derived [OverrideHandler]
+// rebuilt from it.
+type replacement struct {
+	handler    slog.Handler
+	generation uint64
+}
+
+// SetHandler atomically replaces the wrapped handler of the whole family of
+// h, e.g. to rotate the output destination without rebuilding every logger
+// derived from it. Handlers derived with WithAttrs and WithGroup replay
+// their derivations on the new handler, so they keep their attributes and
+// groups, and all of them keep their level overrides. If h is nil, records
+// are discarded with [slog.DiscardHandler].
+//
+// handler takes the place of the handler passed to [New], even when
+// SetHandler is called on a derived handler. Records being handled
+// concurrently may still reach the previous handler.
+func (h *OverrideHandler) SetHandler(handler slog.Handler) {
+	if handler == nil {
+		handler = slog.DiscardHandler
+	}
+	for {
+		current := h.opts.replaced.Load()
+		next := &replacement{handler: handler, generation: 1}
+		if current != nil {
+			next.generation = current.generation + 1
+		}
+		if h.opts.replaced.CompareAndSwap(current, next) {
+			return
+		}
+	}
+}
+
+// root returns the handler passed to New, or its replacement after
+// SetHandler, without the derivations of h, for records concerning the
+// whole family rather than the records of h.
+func (h *OverrideHandler) root() slog.Handler {
+	if r := h.opts.replaced.Load(); r != nil {
+		return r.handler
+	}
+	return h.opts.root
+}
+
+// wrapped returns the handler wrapped by h: the handler passed to New with
+// the derivations of h applied, or, after SetHandler, the replacement with
+// the derivations applied.
+func (h *OverrideHandler) wrapped() slog.Handler {
+	r := h.opts.replaced.Load()
+	if r == nil {
+		return h.basic
+	}
+	if c := h.rebuilt.Load(); c != nil && c.generation == r.generation {
+		return c.handler
+	}
+	handler := r.handler
+	for _, derivation := range h.derivations {
+		handler = derivation(handler)
+	}
+	h.rebuilt.Store(&replacement{handler: handler, generation: r.generation})
+	return handler
+}
@@ -0,0 +1,37 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestSetHandler verifies that derived loggers keep their attributes, groups and level after the handler is replaced
+func TestSetHandler(t *testing.T) {
+	first := slogassert.New(t, slog.LevelDebug, nil)
+	defer first.AssertEmpty()
+	second := slogassert.New(t, slog.LevelDebug, nil)
+	defer second.AssertEmpty()
+
+	handler := NewWithLevel(first, slog.LevelWarn)
+	logger := slog.New(handler).With("component", "db").WithGroup("req")
+
+	logger.Warn("before", "id", 1)
+	handler.SetHandler(second)
+	logger.Info("info after")
+	logger.Warn("after", "id", 2)
+	slog.New(handler).Error("root after")
+
+	first.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "before",
+		Level:   slog.LevelWarn,
+		Attrs:   map[string]any{"component": "db", "req.id": int64(1)},
+	})
+	second.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "after",
+		Level:   slog.LevelWarn,
+		Attrs:   map[string]any{"component": "db", "req.id": int64(2)},
+	})
+	second.AssertMessage("root after")
+}
@@ -0,0 +1,27 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LayerShutdown is the layer set by [OverrideHandler.BeginShutdown]. It is
+// above every predefined layer, since a process shutting down does not go
+// back to normal operation.
+const LayerShutdown Layer = 300
+
+// BeginShutdown switches the handler to Debug at [LayerShutdown], so that
+// the termination sequence, where bugs often hide, is fully logged without
+// keeping Debug on during normal operation. It is subject to the policy set
+// with [SetPolicy].
+func (h *OverrideHandler) BeginShutdown() {
+	h.setLayerLevel(LayerShutdown, "shutdown", slog.LevelDebug)
+}
+
+// DebugOnShutdown calls [OverrideHandler.BeginShutdown] once ctx is done,
+// e.g. for a context from [signal.NotifyContext] canceled when the process
+// is asked to terminate. Calling stop before that prevents the switch.
+func (h *OverrideHandler) DebugOnShutdown(ctx context.Context) (stop func()) {
+	cancel := context.AfterFunc(ctx, h.BeginShutdown)
+	return func() { cancel() }
+}
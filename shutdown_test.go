@@ -0,0 +1,43 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestDebugOnShutdown verifies that Debug is enabled once the shutdown context is done
+func TestDebugOnShutdown(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelInfo)
+	logger := slog.New(handler)
+	ctx, cancel := context.WithCancel(context.Background())
+	handler.DebugOnShutdown(ctx)
+
+	logger.Debug("debug while running")
+	cancel()
+	waitFor(t, "shutdown level", func() bool {
+		_, ok := handler.LayerLeveler(LayerShutdown)
+		return ok
+	})
+	handler.SetLevel(slog.LevelError)
+	logger.Debug("debug while shutting down")
+
+	assertHandler.AssertMessage("debug while shutting down")
+}
+
+// TestDebugOnShutdownStop verifies that stopping prevents the switch
+func TestDebugOnShutdownStop(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := handler.DebugOnShutdown(ctx)
+	stop()
+	cancel()
+	if _, ok := handler.LayerLeveler(LayerShutdown); ok {
+		t.Error("the shutdown layer should not be set after stop")
+	}
+}
@@ -0,0 +1,27 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithSideSink sends the records suppressed by the level threshold to sink
+// instead of dropping them, e.g. to keep full Debug output in a local file
+// while only Info and above reach centralized logging. The level of sink
+// decides which suppressed records it receives.
+//
+// Handlers derived with WithAttrs and WithGroup derive sink the same way. To
+// see suppressed records at all, Enabled reports true for every level sink
+// is enabled for, and the threshold is applied in Handle.
+func WithSideSink(sink slog.Handler) Option {
+	return func(o *options) {
+		o.sideSink = sink
+	}
+}
+
+// handleSide sends a suppressed record to the side sink, if it wants it.
+func (h *OverrideHandler) handleSide(ctx context.Context, record slog.Record) {
+	if h.side != nil && h.side.Enabled(ctx, record.Level) {
+		_ = h.side.Handle(ctx, record)
+	}
+}
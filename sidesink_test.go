@@ -0,0 +1,49 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestSideSink verifies that suppressed records reach the side sink only
+func TestSideSink(t *testing.T) {
+	var out, side bytes.Buffer
+	sink := slog.NewTextHandler(&side, &slog.HandlerOptions{Level: slog.LevelDebug})
+	handler := NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo}, WithSideSink(sink))
+	logger := slog.New(handler).With("component", "db").WithGroup("g")
+
+	logger.Debug("detail", "n", 1)
+	logger.Info("summary")
+
+	if strings.Contains(out.String(), "detail") || !strings.Contains(out.String(), "summary") {
+		t.Errorf("main sink got %q", out.String())
+	}
+	if !strings.Contains(side.String(), "msg=detail component=db g.n=1") || strings.Contains(side.String(), "summary") {
+		t.Errorf("side sink got %q", side.String())
+	}
+
+	side.Reset()
+	handler.SetLevel(slog.LevelDebug)
+	logger.Debug("now main")
+	if side.Len() != 0 || !strings.Contains(out.String(), "now main") {
+		t.Errorf("side sink got %q after lowering the level", side.String())
+	}
+}
+
+// TestSideSinkLevel verifies that the level of the side sink is respected
+func TestSideSinkLevel(t *testing.T) {
+	var side bytes.Buffer
+	sink := slog.NewTextHandler(&side, &slog.HandlerOptions{Level: slog.LevelInfo})
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelError, WithSideSink(sink))
+	if handler.Enabled(t.Context(), slog.LevelDebug) {
+		t.Error("Debug is enabled though neither sink wants it")
+	}
+	logger := slog.New(handler)
+	logger.Debug("dropped")
+	logger.Warn("kept")
+	if strings.Contains(side.String(), "dropped") || !strings.Contains(side.String(), "kept") {
+		t.Errorf("side sink got %q", side.String())
+	}
+}
@@ -0,0 +1,108 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// SourceRule overrides the level of records originating from matching source
+// code locations, resolved from the PC of the record.
+//
+// A rule matches a record if its File is a prefix of a path segment sequence
+// of the source file (so "internal/cache/" matches
+// "/src/app/internal/cache/lru.go"), or if its Function glob matches the fully
+// qualified function name (e.g. "*/internal/cache.*"). In a Function glob, '*'
+// matches any sequence of characters. Empty fields never match.
+type SourceRule struct {
+	File     string
+	Function string
+	Level    slog.Leveler
+}
+
+func (r SourceRule) matches(frame runtime.Frame) bool {
+	if r.File != "" && frame.File != "" {
+		if strings.HasPrefix(frame.File, r.File) || strings.Contains(frame.File, "/"+strings.TrimPrefix(r.File, "/")) {
+			return true
+		}
+	}
+	return r.Function != "" && frame.Function != "" && globMatch(r.Function, frame.Function)
+}
+
+// sourceRules is an immutable set of [SourceRule]s.
+type sourceRules []SourceRule
+
+// minLevel returns the most verbose level of all rules.
+func (rs sourceRules) minLevel() slog.Level {
+	minimum := rs[0].Level.Level()
+	for _, r := range rs[1:] {
+		minimum = min(minimum, r.Level.Level())
+	}
+	return minimum
+}
+
+// match returns the first rule matching the source of pc.
+func (rs sourceRules) match(pc uintptr) (SourceRule, bool) {
+	if pc == 0 {
+		return SourceRule{}, false
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	for _, r := range rs {
+		if r.matches(frame) {
+			return r, true
+		}
+	}
+	return SourceRule{}, false
+}
+
+// SetSourceRules replaces the per-source overrides of this handler and of
+// every handler sharing its configuration. Calling it without rules removes
+// them.
+//
+// Records from a matching source use the level of the first matching rule
+// instead of the regular threshold. Since the source of a record is only
+// known in Handle, Enabled reports true for any level that some rule could
+// accept, and the final decision is made in Handle.
+func (h *OverrideHandler) SetSourceRules(rules ...SourceRule) {
+	var rs sourceRules
+	for _, r := range rules {
+		if r.Level != nil {
+			rs = append(rs, r)
+		}
+	}
+	if len(rs) == 0 {
+		h.opts.sourceRules.Store(nil)
+		return
+	}
+	h.opts.slow.Store(true)
+	h.opts.sourceRules.Store(&rs)
+}
+
+// sourceEnabled reports whether a record at level may pass due to a
+// [SourceRule] even though the regular threshold rejects it.
+func (h *OverrideHandler) sourceEnabled(level slog.Level) bool {
+	rs := h.opts.sourceRules.Load()
+	return rs != nil && level >= rs.minLevel()
+}
+
+// globMatch reports whether s matches pattern, in which '*' matches any
+// sequence of characters, including none.
+func globMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, last)
+}
@@ -0,0 +1,71 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestSourceRulesByFile verifies that records from a matching file use the rule's level
+func TestSourceRulesByFile(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelWarn)
+	handler.SetSourceRules(SourceRule{File: "source_test.go", Level: slog.LevelDebug})
+	logger := slog.New(handler)
+
+	logger.Debug("debug message")
+
+	handler.SetSourceRules(SourceRule{File: "other/file.go", Level: slog.LevelDebug})
+	logger.Debug("debug from other file")
+	logger.Info("info from other file")
+
+	assertHandler.AssertMessage("debug message")
+}
+
+// TestSourceRulesByFunction verifies that function globs are matched against the record's function
+func TestSourceRulesByFunction(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelInfo)
+	logger := slog.New(handler)
+
+	// Rules can also make a source quieter than the regular threshold
+	handler.SetSourceRules(SourceRule{Function: "*.TestSourceRulesByFunction", Level: slog.LevelError})
+	logger.Info("info message")
+	logger.Error("error message")
+
+	// Removing the rules restores the regular threshold
+	handler.SetSourceRules()
+	logger.Debug("debug message")
+	logger.Info("info message")
+
+	assertHandler.AssertMessage("error message")
+	assertHandler.AssertMessage("info message")
+}
+
+// TestGlobMatch verifies the wildcard matching used by function rules
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"a.b", "a.b", true},
+		{"a.b", "a.c", false},
+		{"*", "", true},
+		{"a.*", "a.b.c", true},
+		{"*.Get", "example.com/cache.(*LRU).Get", true},
+		{"*/cache.*", "example.com/cache.(*LRU).Get", true},
+		{"*/cache.*", "example.com/db.Get", false},
+		{"a*b*c", "abbc", true},
+		{"a*b*c", "acb", false},
+	}
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
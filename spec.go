@@ -0,0 +1,136 @@
+package slogleveloverride
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// SpecEntry is a single scope=level pair of a [Spec].
+type SpecEntry struct {
+	// Scope is a registry name, or a pattern in which '*' matches any
+	// sequence of characters.
+	Scope string
+	Level slog.Level
+}
+
+// Spec is a parsed level specification such as "db=debug,http=warn,*=info".
+type Spec []SpecEntry
+
+// ParseSpec parses a comma-separated list of scope=level pairs, as used in
+// flags and environment variables.
+//
+// Levels are parsed like [slog.Level.UnmarshalText], so "debug", "WARN" and
+// "info+2" are all accepted, as are names registered with
+// [RegisterLevelName]. A level without a scope applies to every scope
+// and is equivalent to "*=level". Whitespace around entries is ignored.
+func ParseSpec(s string) (Spec, error) {
+	var spec Spec
+	for entry := range strings.SplitSeq(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		scope, levelText, found := strings.Cut(entry, "=")
+		if !found {
+			scope, levelText = "*", entry
+		}
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			return nil, fmt.Errorf("slogleveloverride: empty scope in %q", entry)
+		}
+		level, err := parseLevel(strings.TrimSpace(levelText))
+		if err != nil {
+			return nil, fmt.Errorf("slogleveloverride: invalid level in %q: %w", entry, err)
+		}
+		spec = append(spec, SpecEntry{Scope: scope, Level: level})
+	}
+	return spec, nil
+}
+
+// String formats the spec in the syntax accepted by [ParseSpec].
+func (s Spec) String() string {
+	entries := make([]string, len(s))
+	for i, e := range s {
+		entries[i] = e.Scope + "=" + e.Level.String()
+	}
+	return strings.Join(entries, ",")
+}
+
+// Lookup returns the level the spec assigns to scope.
+//
+// An entry naming the scope exactly takes precedence over patterns. Among
+// matching patterns, the most specific one, with the most literal
+// characters, wins. If several entries are equally specific, the last one
+// wins.
+func (s Spec) Lookup(scope string) (slog.Level, bool) {
+	best, bestScore := slog.Level(0), -1
+	for _, e := range s {
+		score := scopeSpecificity(e.Scope, scope)
+		if score >= 0 && score >= bestScore {
+			best, bestScore = e.Level, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// scopeSpecificity returns how specifically pattern matches scope, or -1 if
+// it does not match at all.
+func scopeSpecificity(pattern, scope string) int {
+	if pattern == scope {
+		return len(pattern) + 1
+	}
+	if !strings.Contains(pattern, "*") || !globMatch(pattern, scope) {
+		return -1
+	}
+	return len(pattern) - strings.Count(pattern, "*")
+}
+
+// ApplySpec sets the level of every registered handler matched by spec.
+//
+// Handlers not matched by any entry are left unchanged. An entry naming a
+// scope without wildcards that is not registered, or a change rejected by
+// the policy set with [SetPolicy], is reported as an error, in which case no
+// level is changed.
+func (r *Registry) ApplySpec(spec Spec) error {
+	_, err := r.applySpec(spec, SourceCode)
+	return err
+}
+
+// applySpec implements ApplySpec, recording source as the source of the
+// changes. It returns the generation of the change of each handler.
+func (r *Registry) applySpec(spec Spec, source string) (map[*OverrideHandler]uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range spec {
+		if _, ok := r.handlers[e.Scope]; !ok && !strings.Contains(e.Scope, "*") {
+			return nil, fmt.Errorf("slogleveloverride: unknown scope %q", e.Scope)
+		}
+	}
+	for name := range r.handlers {
+		if level, ok := spec.Lookup(name); ok {
+			if err := checkPolicy(name, level); err != nil {
+				return nil, err
+			}
+		}
+	}
+	gens := make(map[*OverrideHandler]uint64)
+	for name, h := range r.handlers {
+		if level, ok := spec.Lookup(name); ok {
+			gens[h] = h.storeLevel(source, level)
+		}
+	}
+	return gens, nil
+}
+
+// parseLevel parses a level name such as "debug" or "warn+2", or a name
+// registered with [RegisterLevelName].
+func parseLevel(s string) (slog.Level, error) {
+	if level, ok := lookupLevelName(s); ok {
+		return level, nil
+	}
+	var level slog.Level
+	err := level.UnmarshalText([]byte(s))
+	return level, err
+}
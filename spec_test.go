@@ -0,0 +1,84 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"testing"
+)
+
+// TestParseSpec verifies parsing of level specifications
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec(" db=debug, http=WARN ,info+2,")
+	if err != nil {
+		t.Fatalf("ParseSpec returned error: %v", err)
+	}
+	want := Spec{
+		{Scope: "db", Level: slog.LevelDebug},
+		{Scope: "http", Level: slog.LevelWarn},
+		{Scope: "*", Level: slog.LevelInfo + 2},
+	}
+	if len(spec) != len(want) {
+		t.Fatalf("ParseSpec returned %v, want %v", spec, want)
+	}
+	for i := range want {
+		if spec[i] != want[i] {
+			t.Fatalf("ParseSpec returned %v, want %v", spec, want)
+		}
+	}
+	if got := spec.String(); got != "db=DEBUG,http=WARN,*=INFO+2" {
+		t.Fatalf("String returned %q", got)
+	}
+
+	for _, invalid := range []string{"db=loud", "=debug"} {
+		if _, err := ParseSpec(invalid); err == nil {
+			t.Errorf("ParseSpec(%q) should return an error", invalid)
+		}
+	}
+}
+
+// TestSpecLookup verifies precedence between exact scopes and patterns
+func TestSpecLookup(t *testing.T) {
+	spec, err := ParseSpec("*=info,db.*=warn,db.sql=debug")
+	if err != nil {
+		t.Fatalf("ParseSpec returned error: %v", err)
+	}
+
+	tests := map[string]slog.Level{
+		"http":     slog.LevelInfo,
+		"db.cache": slog.LevelWarn,
+		"db.sql":   slog.LevelDebug,
+	}
+	for scope, want := range tests {
+		if got, ok := spec.Lookup(scope); !ok || got != want {
+			t.Errorf("Lookup(%q) = (%v, %v), want (%v, true)", scope, got, ok, want)
+		}
+	}
+}
+
+// TestRegistryApplySpec verifies that a spec is applied to registered handlers
+func TestRegistryApplySpec(t *testing.T) {
+	registry := NewRegistry()
+	db := New(slog.DiscardHandler)
+	http := New(slog.DiscardHandler)
+	registry.Register("db", db)
+	registry.Register("http", http)
+
+	spec, _ := ParseSpec("db=debug,*=warn")
+	if err := registry.ApplySpec(spec); err != nil {
+		t.Fatalf("ApplySpec returned error: %v", err)
+	}
+	if level, _ := db.Level(); level != slog.LevelDebug {
+		t.Errorf("db level is %v, want %v", level, slog.LevelDebug)
+	}
+	if level, _ := http.Level(); level != slog.LevelWarn {
+		t.Errorf("http level is %v, want %v", level, slog.LevelWarn)
+	}
+
+	// Unknown scopes are rejected without changing anything
+	spec, _ = ParseSpec("db=error,cache=debug")
+	if err := registry.ApplySpec(spec); err == nil {
+		t.Fatal("ApplySpec should return an error for an unknown scope")
+	}
+	if level, _ := db.Level(); level != slog.LevelDebug {
+		t.Errorf("db level is %v, want %v", level, slog.LevelDebug)
+	}
+}
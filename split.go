@@ -0,0 +1,76 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SplitHandler is a [slog.Handler] replicating the common setup of errors
+// to stderr and the rest to stdout, with both thresholds adjustable at
+// runtime: each sink is behind its own [OverrideHandler], whose level is
+// the threshold of that sink.
+//
+// A record goes to the stderr handler if it is enabled for the level of the
+// record, and otherwise to the stdout handler, so that records are never
+// written to both. With the stderr handler at [slog.LevelError] and the
+// stdout handler at [slog.LevelInfo], Info and Warn records go to stdout
+// and Error records to stderr; raising the stderr threshold sends errors to
+// stdout, and lowering the stdout one adds Debug records there.
+type SplitHandler struct {
+	stdout *OverrideHandler
+	stderr *OverrideHandler
+}
+
+// NewSplitHandler returns a SplitHandler sending records to stderr, or to
+// stdout if stderr is not enabled for them. Register both handlers, e.g.
+// as "stdout" and "stderr" in a [Registry], to control them at runtime.
+func NewSplitHandler(stdout, stderr *OverrideHandler) *SplitHandler {
+	return &SplitHandler{stdout: stdout, stderr: stderr}
+}
+
+// Stdout returns the handler of the stdout sink.
+func (h *SplitHandler) Stdout() *OverrideHandler {
+	return h.stdout
+}
+
+// Stderr returns the handler of the stderr sink.
+func (h *SplitHandler) Stderr() *OverrideHandler {
+	return h.stderr
+}
+
+// Enabled reports whether either sink is enabled for level.
+func (h *SplitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.stderr.Enabled(ctx, level) || h.stdout.Enabled(ctx, level)
+}
+
+// Handle sends record to the stderr handler if it is enabled for its level,
+// and otherwise to the stdout handler.
+func (h *SplitHandler) Handle(ctx context.Context, record slog.Record) error {
+	if h.stderr.Enabled(ctx, record.Level) {
+		return h.stderr.Handle(ctx, record)
+	}
+	if h.stdout.Enabled(ctx, record.Level) {
+		return h.stdout.Handle(ctx, record)
+	}
+	return nil
+}
+
+// WithAttrs returns a SplitHandler whose sinks have attrs added. The
+// derived sinks share the overrides of h, as configured for each handler.
+func (h *SplitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SplitHandler{
+		stdout: h.stdout.WithAttrs(attrs).(*OverrideHandler),
+		stderr: h.stderr.WithAttrs(attrs).(*OverrideHandler),
+	}
+}
+
+// WithGroup returns a SplitHandler whose sinks have the group name added.
+func (h *SplitHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SplitHandler{
+		stdout: h.stdout.WithGroup(name).(*OverrideHandler),
+		stderr: h.stderr.WithGroup(name).(*OverrideHandler),
+	}
+}
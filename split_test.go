@@ -0,0 +1,42 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestSplitHandler verifies that each record goes to exactly one sink and that both thresholds are adjustable
+func TestSplitHandler(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	split := NewSplitHandler(
+		NewWithLevel(slog.NewTextHandler(&stdout, opts), slog.LevelInfo),
+		NewWithLevel(slog.NewTextHandler(&stderr, opts), slog.LevelError),
+	)
+	logger := slog.New(split).With("component", "db")
+
+	logger.Debug("hidden")
+	logger.Warn("to stdout")
+	logger.Error("to stderr")
+	if got := stdout.String(); !strings.Contains(got, `msg="to stdout" component=db`) || strings.Contains(got, "stderr") || strings.Contains(got, "hidden") {
+		t.Errorf("stdout got %q", got)
+	}
+	if got := stderr.String(); !strings.Contains(got, `msg="to stderr" component=db`) || strings.Contains(got, "stdout") {
+		t.Errorf("stderr got %q", got)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	split.Stdout().SetLevel(slog.LevelDebug)
+	split.Stderr().SetLevel(slog.LevelWarn)
+	logger.Debug("now shown")
+	logger.Warn("warn to stderr")
+	if got := stdout.String(); !strings.Contains(got, "now shown") || strings.Contains(got, "warn to stderr") {
+		t.Errorf("stdout got %q after the change", got)
+	}
+	if !strings.Contains(stderr.String(), "warn to stderr") {
+		t.Errorf("stderr got %q after the change", stderr.String())
+	}
+}
@@ -0,0 +1,78 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// DefaultStackKey is the attribute key used by [WithDiagnosticStacks] when
+// no key is given.
+const DefaultStackKey = "stack"
+
+// maxStackDepth bounds the number of frames captured by WithDiagnosticStacks.
+const maxStackDepth = 64
+
+// WithDiagnosticStacks adds the stack trace of the logging call, under the
+// given key or [DefaultStackKey] if key is empty, to Warn and more severe
+// records while diagnostics are active.
+//
+// Diagnostics are active while the effective threshold is
+// [slog.LevelDebug] or lower, or while enabled with
+// [OverrideHandler.SetDiagnostics]. This provides stacks during incidents
+// without changing call sites.
+func WithDiagnosticStacks(key string) Option {
+	if key == "" {
+		key = DefaultStackKey
+	}
+	return func(o *options) {
+		o.stackKey = key
+	}
+}
+
+// SetDiagnostics turns the diagnostic flag of this handler, and of every
+// handler sharing its configuration, on or off. See [WithDiagnosticStacks].
+func (h *OverrideHandler) SetDiagnostics(on bool) {
+	h.opts.diagnostics.Store(on)
+}
+
+// stackAttr returns the stack trace attribute for record, if due.
+func (h *OverrideHandler) stackAttr(threshold slog.Level, record slog.Record) (slog.Attr, bool) {
+	if h.opts.stackKey == "" || record.Level < slog.LevelWarn {
+		return slog.Attr{}, false
+	}
+	if threshold > slog.LevelDebug && !h.opts.diagnostics.Load() {
+		return slog.Attr{}, false
+	}
+	return slog.String(h.opts.stackKey, callerStack(record.PC)), true
+}
+
+// callerStack formats the stack of the current goroutine, starting at the
+// frame of pc if it is part of it.
+func callerStack(pc uintptr) string {
+	pcs := make([]uintptr, maxStackDepth)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+	for i, p := range pcs {
+		if p == pc {
+			pcs = pcs[i:]
+			break
+		}
+	}
+
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
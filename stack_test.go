@@ -0,0 +1,45 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestWithDiagnosticStacks verifies that stacks are added to Warn+ records while diagnostics are active
+func TestWithDiagnosticStacks(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelInfo, WithDiagnosticStacks(""))
+	logger := slog.New(handler)
+
+	hasStack := func(v slog.Value) bool {
+		return strings.HasPrefix(v.String(), "github.com/martin-viggiano/slog-level-override.TestWithDiagnosticStacks\n")
+	}
+
+	logger.Warn("without diagnostics")
+
+	handler.SetLevel(slog.LevelDebug)
+	logger.Info("info with debug override")
+	logger.Warn("warn with debug override")
+
+	handler.SetLevel(slog.LevelInfo)
+	handler.SetDiagnostics(true)
+	logger.Error("error with diagnostics")
+
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{Message: "without diagnostics", Level: slog.LevelWarn, AllAttrsMatch: true})
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{Message: "info with debug override", Level: slog.LevelInfo, AllAttrsMatch: true})
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "warn with debug override",
+		Level:   slog.LevelWarn,
+		Attrs:   map[string]any{DefaultStackKey: hasStack},
+	})
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "error with diagnostics",
+		Level:   slog.LevelError,
+		Attrs:   map[string]any{DefaultStackKey: hasStack},
+	})
+}
@@ -0,0 +1,110 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// RemoteLeveler is a [slog.Leveler] holding the last level fetched from a
+// remote source, such as etcd, Consul or an HTTP service, and tracking how
+// stale it is.
+//
+// The integration fetching the level calls Update after every successful
+// fetch and Fail after every failed one. While the source cannot be
+// reached, the last-known level stays in effect; once no update has
+// succeeded for longer than MaxStaleness, the leveler falls back to Safe.
+// A MaxStaleness of zero keeps the last-known level forever.
+//
+// The fields must not be changed after the first call to Level.
+type RemoteLeveler struct {
+	MaxStaleness time.Duration
+	Safe         slog.Level
+
+	// now returns the current time.
+	now func() time.Time
+
+	level   atomic.Int64
+	updated atomic.Int64
+	err     atomic.Pointer[error]
+}
+
+// RemoteStatus is the state of a [RemoteLeveler], e.g. for a status page
+// or metrics.
+type RemoteStatus struct {
+	// Level is the level in effect.
+	Level slog.Level
+	// Updated is the time of the last successful update.
+	Updated time.Time
+	// Staleness is the time elapsed since Updated.
+	Staleness time.Duration
+	// Stale reports whether Staleness exceeds MaxStaleness, so that Level
+	// is the safe level.
+	Stale bool
+	// Err is the error of the last failed fetch, if no update succeeded
+	// since.
+	Err error
+}
+
+// NewRemoteLeveler creates a [RemoteLeveler] starting at initial, falling
+// back to safe once no update has succeeded for maxStaleness.
+func NewRemoteLeveler(initial slog.Level, maxStaleness time.Duration, safe slog.Level) *RemoteLeveler {
+	l := &RemoteLeveler{MaxStaleness: maxStaleness, Safe: safe}
+	l.level.Store(int64(initial))
+	l.updated.Store(time.Now().UnixNano())
+	return l
+}
+
+// Update records level as fetched successfully now.
+func (l *RemoteLeveler) Update(level slog.Level) {
+	l.level.Store(int64(level))
+	l.updated.Store(l.clock().UnixNano())
+	l.err.Store(nil)
+}
+
+// touch records a successful fetch that found the level unchanged.
+func (l *RemoteLeveler) touch() {
+	l.updated.Store(l.clock().UnixNano())
+	l.err.Store(nil)
+}
+
+// Fail records a failed fetch. The last-known level stays in effect until
+// it is stale.
+func (l *RemoteLeveler) Fail(err error) {
+	l.err.Store(&err)
+}
+
+// Level returns the last-known level, or Safe if it is stale.
+func (l *RemoteLeveler) Level() slog.Level {
+	if l.MaxStaleness > 0 && l.staleness() > l.MaxStaleness {
+		return l.Safe
+	}
+	return slog.Level(l.level.Load())
+}
+
+// Status returns the current state of the leveler.
+func (l *RemoteLeveler) Status() RemoteStatus {
+	s := RemoteStatus{
+		Level:     l.Level(),
+		Updated:   time.Unix(0, l.updated.Load()),
+		Staleness: l.staleness(),
+	}
+	s.Stale = l.MaxStaleness > 0 && s.Staleness > l.MaxStaleness
+	if err := l.err.Load(); err != nil {
+		s.Err = *err
+	}
+	return s
+}
+
+// staleness returns the time elapsed since the last successful update.
+func (l *RemoteLeveler) staleness() time.Duration {
+	return l.clock().Sub(time.Unix(0, l.updated.Load()))
+}
+
+// clock returns the current time.
+func (l *RemoteLeveler) clock() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
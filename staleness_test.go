@@ -0,0 +1,42 @@
+package slogleveloverride
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestRemoteLeveler verifies that the last-known level is kept until it is stale
+func TestRemoteLeveler(t *testing.T) {
+	now := time.Now()
+	leveler := NewRemoteLeveler(slog.LevelInfo, time.Minute, slog.LevelWarn)
+	leveler.now = func() time.Time { return now }
+
+	handler := NewWithLevel(slog.DiscardHandler, leveler)
+	leveler.Update(slog.LevelDebug)
+	if !handler.Enabled(t.Context(), slog.LevelDebug) {
+		t.Error("debug should be enabled after the update")
+	}
+
+	errUnreachable := errors.New("unreachable")
+	leveler.Fail(errUnreachable)
+	now = now.Add(30 * time.Second)
+	status := leveler.Status()
+	if status.Level != slog.LevelDebug || status.Stale || status.Staleness != 30*time.Second || !errors.Is(status.Err, errUnreachable) {
+		t.Errorf("status while reachable recently = %+v", status)
+	}
+
+	now = now.Add(time.Minute)
+	if handler.Enabled(t.Context(), slog.LevelInfo) {
+		t.Error("a stale leveler should fall back to the safe level")
+	}
+	if status := leveler.Status(); status.Level != slog.LevelWarn || !status.Stale {
+		t.Errorf("status once stale = %+v", status)
+	}
+
+	leveler.Update(slog.LevelInfo)
+	if status := leveler.Status(); status.Level != slog.LevelInfo || status.Stale || status.Err != nil {
+		t.Errorf("status after recovery = %+v", status)
+	}
+}
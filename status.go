@@ -0,0 +1,117 @@
+package slogleveloverride
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// statusDocument is the document returned by [Registry.Status].
+type statusDocument struct {
+	Handlers []handlerStatus `json:"handlers"`
+}
+
+// handlerStatus is the status of a registered handler.
+type handlerStatus struct {
+	Name     string            `json:"name"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Level    string            `json:"level"`
+	Origin   string            `json:"origin"`
+	Layer    string            `json:"layer,omitempty"`
+	Source   string            `json:"source,omitempty"`
+	Layers   map[string]string `json:"layers,omitempty"`
+	Features []string          `json:"features,omitempty"`
+	Stats    handlerStats      `json:"stats"`
+	Remote   *remoteStatus     `json:"remote,omitempty"`
+}
+
+// handlerStats are the counters and flags of a handler.
+type handlerStats struct {
+	Generation     uint64        `json:"generation"`
+	Muted          bool          `json:"muted"`
+	Forced         bool          `json:"forced"`
+	FallbackActive bool          `json:"fallback_active"`
+	Memory         *MemoryStatus `json:"memory,omitempty"`
+}
+
+// remoteStatus is the [RemoteStatus] of the remote leveler in effect.
+type remoteStatus struct {
+	Level            string    `json:"level"`
+	Updated          time.Time `json:"updated"`
+	StalenessSeconds float64   `json:"staleness_seconds"`
+	Stale            bool      `json:"stale"`
+	Error            string    `json:"error,omitempty"`
+}
+
+// Status returns a JSON document describing every registered handler, for
+// fleet tooling and admin interfaces. For each handler, sorted by name, it
+// holds its name and labels, its effective level with its origin, layer
+// and source as reported by [OverrideHandler.EffectiveLevel], the overrides
+// of all layers, the features in use as reported by
+// [OverrideHandler.Describe], counters such as the generation of the
+// override and the usage of its [MemoryBudget], and, if the override in
+// effect is a [RemoteLeveler] or embeds one, its staleness. For example:
+//
+//	{"handlers": [{"name": "db", "level": "DEBUG",
+//	  "origin": "override at layer base from control", "layer": "base",
+//	  "source": "control", "layers": {"base": "DEBUG"},
+//	  "stats": {"generation": 3, "muted": false, "forced": false,
+//	  "fallback_active": false}}]}
+func (r *Registry) Status() ([]byte, error) {
+	doc := statusDocument{Handlers: []handlerStatus{}}
+	for _, name := range r.Names() {
+		h, ok := r.Handler(name)
+		if !ok {
+			continue
+		}
+		s := h.status()
+		s.Name = name
+		s.Labels = r.Labels(name)
+		doc.Handlers = append(doc.Handlers, s)
+	}
+	return json.Marshal(doc)
+}
+
+// status returns the status of h, without name and labels.
+func (h *OverrideHandler) status() handlerStatus {
+	level, origin := h.EffectiveLevel()
+	s := handlerStatus{
+		Level:    LevelName(level),
+		Origin:   origin.String(),
+		Source:   origin.Source,
+		Features: h.opts.features(),
+		Stats: handlerStats{
+			Generation:     h.Generation(),
+			Muted:          h.state.muted.Load(),
+			Forced:         h.state.forced.Load(),
+			FallbackActive: h.FallbackActive(),
+		},
+	}
+	if origin.Kind == OriginOverride {
+		s.Layer = origin.Layer.String()
+	}
+	if layers := h.Layers(); len(layers) > 0 {
+		s.Layers = make(map[string]string, len(layers))
+		for layer, leveler := range layers {
+			s.Layers[layer.String()] = LevelName(leveler.Level())
+		}
+	}
+	if h.opts.memory != nil {
+		memory := h.opts.memory.Status()
+		s.Stats.Memory = &memory
+	}
+	if leveler, ok := h.Leveler(); ok {
+		if remote, ok := leveler.(interface{ Status() RemoteStatus }); ok {
+			rs := remote.Status()
+			s.Remote = &remoteStatus{
+				Level:            LevelName(rs.Level),
+				Updated:          rs.Updated,
+				StalenessSeconds: rs.Staleness.Seconds(),
+				Stale:            rs.Stale,
+			}
+			if rs.Err != nil {
+				s.Remote.Error = rs.Err.Error()
+			}
+		}
+	}
+	return s
+}
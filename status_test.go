@@ -0,0 +1,72 @@
+package slogleveloverride
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestRegistryStatus verifies the status document of registered handlers
+func TestRegistryStatus(t *testing.T) {
+	registry := NewRegistry()
+	db := NewWithLevel(slog.DiscardHandler, slog.LevelInfo, WithMemoryBudget(NewMemoryBudget(1024)))
+	registry.RegisterWithLabels("db", db, map[string]string{"tier": "backend"})
+	remote := NewRemoteLeveler(slog.LevelDebug, time.Minute, slog.LevelWarn)
+	remote.Fail(errors.New("unreachable"))
+	http := New(slog.DiscardHandler)
+	http.SetLayerLevel(LayerRemote, remote)
+	registry.Register("http", http)
+	registry.Register("plain", New(slog.NewTextHandler(nil, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	if !registry.SetLevel("db", slog.LevelDebug) {
+		t.Fatal("SetLevel failed")
+	}
+
+	data, err := registry.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	var doc struct {
+		Handlers []struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+			Level  string            `json:"level"`
+			Layer  string            `json:"layer"`
+			Source string            `json:"source"`
+			Layers map[string]string `json:"layers"`
+			Stats  struct {
+				Generation uint64 `json:"generation"`
+				Memory     *struct {
+					Max int64 `json:"max"`
+				} `json:"memory"`
+			} `json:"stats"`
+			Remote *struct {
+				Level string `json:"level"`
+				Stale bool   `json:"stale"`
+				Error string `json:"error"`
+			} `json:"remote"`
+		} `json:"handlers"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid document %s: %v", data, err)
+	}
+	if len(doc.Handlers) != 3 {
+		t.Fatalf("document %s, want 3 handlers", data)
+	}
+
+	dbStatus, httpStatus, plain := doc.Handlers[0], doc.Handlers[1], doc.Handlers[2]
+	if dbStatus.Name != "db" || dbStatus.Labels["tier"] != "backend" || dbStatus.Level != "DEBUG" ||
+		dbStatus.Layer != "base" || dbStatus.Source != SourceCode || dbStatus.Layers["base"] != "DEBUG" {
+		t.Errorf("db status %+v", dbStatus)
+	}
+	if dbStatus.Stats.Generation != db.Generation() || dbStatus.Stats.Memory == nil || dbStatus.Stats.Memory.Max != 1024 {
+		t.Errorf("db stats %+v", dbStatus.Stats)
+	}
+	if httpStatus.Remote == nil || httpStatus.Remote.Level != "DEBUG" || httpStatus.Remote.Stale || httpStatus.Remote.Error != "unreachable" {
+		t.Errorf("http remote status %+v", httpStatus.Remote)
+	}
+	if plain.Level != "WARN" || plain.Layer != "" || plain.Remote != nil {
+		t.Errorf("plain status %+v", plain)
+	}
+}
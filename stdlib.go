@@ -0,0 +1,45 @@
+package slogleveloverride
+
+import (
+	"io"
+	"log/slog"
+)
+
+// NewTextHandler creates an [OverrideHandler] wrapping a [slog.TextHandler]
+// writing to w.
+//
+// The override starts at opts.Level, or [slog.LevelInfo] if not set. The
+// text handler keeps that initial level as its own, which applies again once
+// the override is cleared. Since the text handler only filters in Enabled,
+// which the override answers, it never drops records the override lets
+// through.
+func NewTextHandler(w io.Writer, opts *slog.HandlerOptions, options ...Option) *OverrideHandler {
+	handlerOpts, level := takeoverOptions(opts)
+	return NewWithLevel(slog.NewTextHandler(w, &handlerOpts), level, options...)
+}
+
+// NewJSONHandler creates an [OverrideHandler] wrapping a [slog.JSONHandler]
+// writing to w, with the same level handling as [NewTextHandler].
+func NewJSONHandler(w io.Writer, opts *slog.HandlerOptions, options ...Option) *OverrideHandler {
+	handlerOpts, level := takeoverOptions(opts)
+	return NewWithLevel(slog.NewJSONHandler(w, &handlerOpts), level, options...)
+}
+
+// takeoverOptions returns a copy of opts along with the level to use as
+// initial override: opts.Level, or [slog.LevelInfo] if not set. The level
+// of the copy is a [slog.LevelVar] of its own holding the initial level, so
+// that the handler falls back to it once the override is cleared.
+func takeoverOptions(opts *slog.HandlerOptions) (slog.HandlerOptions, slog.Leveler) {
+	var handlerOpts slog.HandlerOptions
+	if opts != nil {
+		handlerOpts = *opts
+	}
+	var level slog.Leveler = slog.LevelInfo
+	if handlerOpts.Level != nil {
+		level = handlerOpts.Level
+	}
+	floor := new(slog.LevelVar)
+	floor.Set(level.Level())
+	handlerOpts.Level = floor
+	return handlerOpts, level
+}
@@ -0,0 +1,79 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestNewTextHandler verifies that the override is the only level floor of the text handler
+func TestNewTextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})
+	logger := slog.New(handler)
+
+	logger.Info("dropped by initial level")
+	handler.SetLevel(slog.LevelDebug - 4)
+	logger.Log(t.Context(), slog.LevelDebug-4, "trace after lowering")
+
+	out := buf.String()
+	if strings.Contains(out, "dropped by initial level") {
+		t.Errorf("record below the initial level was written: %q", out)
+	}
+	if !strings.Contains(out, "msg=\"trace after lowering\"") {
+		t.Errorf("record at the lowered override was not written: %q", out)
+	}
+}
+
+// TestNewTextHandlerClearLevel verifies that the initial level applies again once the override is cleared
+func TestNewTextHandlerClearLevel(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewTextHandler(&buf, nil)
+	logger := slog.New(handler)
+
+	handler.SetLevel(slog.LevelDebug)
+	logger.Debug("written while lowered")
+	handler.ClearLevel()
+	logger.Debug("dropped after clear")
+	logger.Info("written after clear")
+
+	out := buf.String()
+	if strings.Contains(out, "dropped after clear") {
+		t.Errorf("DEBUG record written after ClearLevel: %q", out)
+	}
+	for _, msg := range []string{"written while lowered", "written after clear"} {
+		if !strings.Contains(out, msg) {
+			t.Errorf("record %q was not written: %q", msg, out)
+		}
+	}
+}
+
+// TestNewJSONHandler verifies the default level and option handling of the JSON handler
+func TestNewJSONHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewJSONHandler(&buf, nil)
+	logger := slog.New(handler)
+
+	logger.Debug("dropped by default level")
+	logger.Info("written")
+	if level, ok := handler.Level(); !ok || level != slog.LevelInfo {
+		t.Fatalf("Level = (%v, %v), want (%v, true)", level, ok, slog.LevelInfo)
+	}
+
+	handler.SetLevel(slog.LevelDebug)
+	logger.Debug("written after lowering")
+
+	var msgs []string
+	for line := range strings.Lines(buf.String()) {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		msgs = append(msgs, entry["msg"].(string))
+	}
+	if want := []string{"written", "written after lowering"}; strings.Join(msgs, ",") != strings.Join(want, ",") {
+		t.Errorf("messages = %q, want %q", msgs, want)
+	}
+}
@@ -0,0 +1,160 @@
+package slogleveloverride
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// StreamLeveler is a [RemoteLeveler] fed by a long-lived Server-Sent Events
+// connection to a control service, applying pushed level changes
+// immediately instead of waiting for the next poll like [HTTPLeveler].
+//
+// The data of each event is a document as served to [HTTPLeveler]: a
+// single level or, if OnRules is set, rules as accepted by [ParseRules] or
+// a JSON array of [Rule]s. Comment lines, which SSE servers send as
+// heartbeats, and events mark the level as fresh, so the server should send
+// them more often than MaxStaleness.
+//
+// When the connection fails or ends, the client reconnects after a backoff
+// doubling from MinBackoff up to MaxBackoff, reset once connected. The
+// health of the stream is reported by Connected and Status.
+//
+// The fields must not be changed after Start.
+type StreamLeveler struct {
+	*RemoteLeveler
+
+	// URL is the address of the event stream.
+	URL string
+	// Client sends the requests. It must not have a timeout shorter than
+	// the life of a connection. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// OnRules receives the rules of rules documents. If nil, only levels
+	// are accepted.
+	OnRules func([]Rule)
+	// MinBackoff and MaxBackoff bound the wait before reconnecting. If
+	// MaxBackoff is zero or negative, one minute is used; if MinBackoff is,
+	// one second or MaxBackoff, whichever is shorter.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	connected atomic.Bool
+}
+
+// NewStreamLeveler creates a [StreamLeveler] connecting to url, starting at
+// initial until the first event, and reconnecting after 1s to 1m.
+func NewStreamLeveler(url string, initial slog.Level) *StreamLeveler {
+	return &StreamLeveler{
+		RemoteLeveler: NewRemoteLeveler(initial, 0, initial),
+		URL:           url,
+		MinBackoff:    time.Second,
+		MaxBackoff:    time.Minute,
+	}
+}
+
+// Connected reports whether the stream is currently connected.
+func (l *StreamLeveler) Connected() bool {
+	return l.connected.Load()
+}
+
+// Start connects to the stream and keeps reconnecting until ctx is done or
+// stop is called. Connection errors are recorded with Fail, keeping the
+// last-known level.
+func (l *StreamLeveler) Start(ctx context.Context) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	minBackoff, maxBackoff := l.backoffs()
+	go func() {
+		backoff := minBackoff
+		for {
+			connected, err := l.stream(ctx)
+			l.connected.Store(false)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				l.Fail(fmt.Errorf("slogleveloverride: streaming %s: %w", l.URL, err))
+			}
+			if connected {
+				backoff = minBackoff
+			}
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			backoff = min(2*backoff, maxBackoff)
+		}
+	}()
+	return cancel
+}
+
+// backoffs returns the bounds of the wait before reconnecting, with the
+// defaults applied.
+func (l *StreamLeveler) backoffs() (minBackoff, maxBackoff time.Duration) {
+	minBackoff, maxBackoff = l.MinBackoff, l.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+	if minBackoff <= 0 {
+		minBackoff = min(time.Second, maxBackoff)
+	}
+	return minBackoff, max(minBackoff, maxBackoff)
+}
+
+// stream reads one connection until it ends, and reports whether it was
+// established.
+func (l *StreamLeveler) stream(ctx context.Context) (connected bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.URL, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	l.connected.Store(true)
+	l.touch()
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				text := strings.Join(data, "\n")
+				if err := applyDocument(l.RemoteLeveler, l.OnRules, strings.HasPrefix(text, "["), text); err != nil {
+					l.Fail(fmt.Errorf("slogleveloverride: streaming %s: %w", l.URL, err))
+				}
+				data = data[:0]
+			}
+		case strings.HasPrefix(line, ":"):
+			l.touch()
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			if field == "data" {
+				data = append(data, strings.TrimPrefix(value, " "))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return true, err
+	}
+	return true, fmt.Errorf("stream ended")
+}
@@ -0,0 +1,78 @@
+package slogleveloverride
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestStreamLeveler verifies that pushed levels are applied and the stream reconnects
+func TestStreamLeveler(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			http.Error(w, "not an event stream request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch connections.Add(1) {
+		case 1:
+			// The first connection pushes a level, then drops
+			fmt.Fprint(w, ": heartbeat\n\nevent: level\ndata: warn\n\n")
+		default:
+			fmt.Fprint(w, "data: level>=debug attr.tenant=acme\ndata: level>=error\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	rules := make(chan []Rule, 1)
+	leveler := NewStreamLeveler(server.URL, slog.LevelInfo)
+	leveler.MinBackoff = time.Millisecond
+	leveler.OnRules = func(rs []Rule) { rules <- rs }
+	stop := leveler.Start(t.Context())
+	defer stop()
+
+	waitFor(t, "pushed level", func() bool { return leveler.Level() == slog.LevelWarn })
+	select {
+	case rs := <-rules:
+		// The data lines of an event are joined by newlines, one rule each
+		if len(rs) != 2 {
+			t.Errorf("rules = %+v", rs)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the rules pushed after reconnecting")
+	}
+	if !leveler.Connected() {
+		t.Error("the stream should be connected")
+	}
+
+	stop()
+	waitFor(t, "disconnection", func() bool { return !leveler.Connected() })
+}
+
+// TestStreamLevelerBackoffs verifies that non-positive backoffs fall back to positive defaults
+func TestStreamLevelerBackoffs(t *testing.T) {
+	tests := []struct {
+		minBackoff, maxBackoff time.Duration
+		wantMin, wantMax       time.Duration
+	}{
+		{time.Second, time.Minute, time.Second, time.Minute},
+		{0, 0, time.Second, time.Minute},
+		{0, 100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond},
+		{-time.Second, 10 * time.Second, time.Second, 10 * time.Second},
+		{time.Minute, time.Second, time.Minute, time.Minute},
+	}
+	for _, tt := range tests {
+		leveler := &StreamLeveler{MinBackoff: tt.minBackoff, MaxBackoff: tt.maxBackoff}
+		if gotMin, gotMax := leveler.backoffs(); gotMin != tt.wantMin || gotMax != tt.wantMax {
+			t.Errorf("backoffs(%v, %v) = %v, %v, want %v, %v",
+				tt.minBackoff, tt.maxBackoff, gotMin, gotMax, tt.wantMin, tt.wantMax)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// suppressedBuffer keeps the most recent suppressed records, rendered as
+// text lines, within a bound on their number and total size.
+type suppressedBuffer struct {
+	maxRecords int
+	maxBytes   int
+	budget     *MemoryBudget
+
+	mu    sync.Mutex
+	lines [][]byte
+	size  int
+	buf   bytes.Buffer
+	text  slog.Handler
+}
+
+// WithSuppressedBuffer keeps the most recent records suppressed by the level
+// threshold in memory, up to maxRecords records and maxBytes bytes of
+// rendered text, whichever is reached first, dropping the oldest ones. A
+// limit of zero or less means no limit on that dimension. The records can
+// be retrieved on demand with [OverrideHandler.DumpSuppressed] or the "dump"
+// command of [ControlServer], giving recent Debug context without it ever
+// reaching the underlying handler.
+//
+// Records are rendered like a [slog.TextHandler] would when suppressed. To
+// see suppressed records at all, Enabled reports true for every level and
+// the threshold is applied in Handle, so records below the threshold are
+// still built by the logger.
+func WithSuppressedBuffer(maxRecords, maxBytes int) Option {
+	return func(o *options) {
+		b := &suppressedBuffer{maxRecords: maxRecords, maxBytes: maxBytes}
+		b.text = slog.NewTextHandler(&b.buf, &slog.HandlerOptions{Level: slog.Level(-probeRange)})
+		o.suppressed = b
+	}
+}
+
+// add renders and keeps record, along with the attributes attrs of the
+// handler that received it.
+func (b *suppressedBuffer) add(record slog.Record, attrs []slog.Attr) {
+	if len(attrs) > 0 {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+	if err := b.text.Handle(context.Background(), record); err != nil {
+		return
+	}
+	line := bytes.Clone(b.buf.Bytes())
+	if b.maxBytes > 0 && len(line) > b.maxBytes {
+		return
+	}
+	drop := 0
+	for !b.budget.reserve(int64(len(line))) {
+		if drop == len(b.lines) {
+			b.budget.reject()
+			b.lines = append(b.lines[:0], b.lines[drop:]...)
+			return
+		}
+		b.size -= len(b.lines[drop])
+		b.budget.evict(int64(len(b.lines[drop])))
+		drop++
+	}
+	b.lines = append(b.lines, line)
+	b.size += len(line)
+	for (b.maxRecords > 0 && len(b.lines)-drop > b.maxRecords) || (b.maxBytes > 0 && b.size > b.maxBytes) {
+		b.size -= len(b.lines[drop])
+		b.budget.release(int64(len(b.lines[drop])))
+		drop++
+	}
+	if drop > 0 {
+		b.lines = append(b.lines[:0], b.lines[drop:]...)
+	}
+}
+
+// snapshot returns the kept lines, oldest first.
+func (b *suppressedBuffer) snapshot() [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([][]byte(nil), b.lines...)
+}
+
+// drain returns the kept lines, oldest first, and empties the buffer.
+func (b *suppressedBuffer) drain() [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lines := b.lines
+	b.budget.release(int64(b.size))
+	b.lines, b.size = nil, 0
+	return lines
+}
+
+// DumpSuppressed writes the records kept by [WithSuppressedBuffer] to w, one
+// text line per record, oldest first. It writes nothing without
+// WithSuppressedBuffer.
+func (h *OverrideHandler) DumpSuppressed(w io.Writer) error {
+	if h.opts.suppressed == nil {
+		return nil
+	}
+	for _, line := range h.opts.suppressed.snapshot() {
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
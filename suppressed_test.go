@@ -0,0 +1,54 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestSuppressedBuffer verifies that the most recent suppressed records are kept and dumped
+func TestSuppressedBuffer(t *testing.T) {
+	var out bytes.Buffer
+	handler := NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo}, WithSuppressedBuffer(2, 0))
+	logger := slog.New(handler).With("component", "db")
+
+	logger.Debug("dropped by record limit")
+	logger.Debug("kept 1", "n", 1)
+	logger.Info("forwarded")
+	logger.Debug("kept 2", "n", 2)
+
+	var dump bytes.Buffer
+	if err := handler.DumpSuppressed(&dump); err != nil {
+		t.Fatalf("DumpSuppressed failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(dump.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("dumped %d lines, want 2: %q", len(lines), dump.String())
+	}
+	for i, want := range []string{`msg="kept 1" n=1 component=db`, `msg="kept 2" n=2 component=db`} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d is %q, want it to contain %q", i, lines[i], want)
+		}
+	}
+	if strings.Contains(out.String(), "kept") {
+		t.Errorf("suppressed records reached the underlying handler: %q", out.String())
+	}
+}
+
+// TestSuppressedBufferBytes verifies the bound on the rendered size
+func TestSuppressedBufferBytes(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelInfo, WithSuppressedBuffer(0, 150))
+	logger := slog.New(handler)
+	for range 10 {
+		logger.Debug("a debug record of some length")
+	}
+
+	var dump bytes.Buffer
+	if err := handler.DumpSuppressed(&dump); err != nil {
+		t.Fatalf("DumpSuppressed failed: %v", err)
+	}
+	if dump.Len() > 150 || dump.Len() == 0 {
+		t.Fatalf("dumped %d bytes, want between 1 and 150", dump.Len())
+	}
+}
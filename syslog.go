@@ -0,0 +1,130 @@
+//go:build !windows && !plan9 && !js && !wasip1
+
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"log/syslog"
+	"strings"
+	"sync"
+)
+
+// SyslogSeverity maps a level to the closest RFC 5424 severity.
+//
+// The standard levels map to their namesakes, and each range of four levels
+// above them to the next severity, so custom levels such as NOTICE
+// (Info+2) or FATAL (Error+4) get a sensible severity:
+//
+//	..Info-1      LOG_DEBUG
+//	Info..Info+1  LOG_INFO
+//	Info+2..+3    LOG_NOTICE
+//	Warn..+3      LOG_WARNING
+//	Error..+3     LOG_ERR
+//	Error+4..+7   LOG_CRIT
+//	Error+8..+11  LOG_ALERT
+//	Error+12..    LOG_EMERG
+func SyslogSeverity(level slog.Level) syslog.Priority {
+	switch {
+	case level < slog.LevelInfo:
+		return syslog.LOG_DEBUG
+	case level < slog.LevelInfo+2:
+		return syslog.LOG_INFO
+	case level < slog.LevelWarn:
+		return syslog.LOG_NOTICE
+	case level < slog.LevelError:
+		return syslog.LOG_WARNING
+	case level < slog.LevelError+4:
+		return syslog.LOG_ERR
+	case level < slog.LevelError+8:
+		return syslog.LOG_CRIT
+	case level < slog.LevelError+12:
+		return syslog.LOG_ALERT
+	default:
+		return syslog.LOG_EMERG
+	}
+}
+
+// NewSyslogHandler creates an [OverrideHandler] writing records formatted by
+// a [slog.TextHandler] to w, with the severity given by [SyslogSeverity].
+//
+// Levels are handled like in [NewTextHandler]: the override starts at
+// opts.Level, or [slog.LevelInfo] if not set, which applies again once the
+// override is cleared. The time attribute is omitted, since syslog records carry their own
+// timestamp.
+func NewSyslogHandler(w *syslog.Writer, opts *slog.HandlerOptions, options ...Option) *OverrideHandler {
+	textOpts, level := takeoverOptions(opts)
+	replace := textOpts.ReplaceAttr
+	textOpts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+		if replace != nil {
+			return replace(groups, a)
+		}
+		return a
+	}
+
+	sw := &syslogWriter{w: w}
+	return NewWithLevel(&syslogHandler{
+		Handler: slog.NewTextHandler(sw, &textOpts),
+		sw:      sw,
+	}, level, options...)
+}
+
+// syslogWriter writes each formatted record to the syslog writer with the
+// severity of the record being handled.
+type syslogWriter struct {
+	mu       sync.Mutex
+	w        *syslog.Writer
+	severity syslog.Priority
+}
+
+func (sw *syslogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	var err error
+	switch sw.severity {
+	case syslog.LOG_EMERG:
+		err = sw.w.Emerg(msg)
+	case syslog.LOG_ALERT:
+		err = sw.w.Alert(msg)
+	case syslog.LOG_CRIT:
+		err = sw.w.Crit(msg)
+	case syslog.LOG_ERR:
+		err = sw.w.Err(msg)
+	case syslog.LOG_WARNING:
+		err = sw.w.Warning(msg)
+	case syslog.LOG_NOTICE:
+		err = sw.w.Notice(msg)
+	case syslog.LOG_INFO:
+		err = sw.w.Info(msg)
+	default:
+		err = sw.w.Debug(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// syslogHandler sets the severity of the shared writer before formatting
+// each record.
+type syslogHandler struct {
+	slog.Handler
+	sw *syslogWriter
+}
+
+func (h *syslogHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.sw.mu.Lock()
+	defer h.sw.mu.Unlock()
+	h.sw.severity = SyslogSeverity(record.Level)
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *syslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &syslogHandler{Handler: h.Handler.WithAttrs(attrs), sw: h.sw}
+}
+
+func (h *syslogHandler) WithGroup(name string) slog.Handler {
+	return &syslogHandler{Handler: h.Handler.WithGroup(name), sw: h.sw}
+}
@@ -0,0 +1,77 @@
+//go:build !windows && !plan9 && !js && !wasip1
+
+package slogleveloverride
+
+import (
+	"log/slog"
+	"log/syslog"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSyslogSeverity verifies the mapping of standard and custom levels
+func TestSyslogSeverity(t *testing.T) {
+	tests := map[slog.Level]syslog.Priority{
+		slog.LevelDebug - 4: syslog.LOG_DEBUG,
+		slog.LevelDebug:     syslog.LOG_DEBUG,
+		slog.LevelInfo:      syslog.LOG_INFO,
+		slog.LevelInfo + 2:  syslog.LOG_NOTICE,
+		slog.LevelWarn:      syslog.LOG_WARNING,
+		slog.LevelError:     syslog.LOG_ERR,
+		slog.LevelError + 4: syslog.LOG_CRIT,
+		slog.LevelError + 8: syslog.LOG_ALERT,
+		slog.Level(100):     syslog.LOG_EMERG,
+	}
+	for level, want := range tests {
+		if got := SyslogSeverity(level); got != want {
+			t.Errorf("SyslogSeverity(%v) = %v, want %v", level, got, want)
+		}
+	}
+}
+
+// TestSyslogHandler verifies that records reach syslog with their severity and honor the override
+func TestSyslogHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "syslog.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	defer conn.Close()
+
+	w, err := syslog.Dial("unixgram", path, syslog.LOG_LOCAL0, "test")
+	if err != nil {
+		t.Fatalf("dialing syslog: %v", err)
+	}
+	defer w.Close()
+
+	handler := NewSyslogHandler(w, &slog.HandlerOptions{Level: slog.LevelWarn})
+	logger := slog.New(handler).With("component", "db")
+
+	logger.Info("dropped")
+	logger.Warn("disk almost full")
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("reading syslog message: %v", err)
+	}
+	msg := string(buf[:n])
+
+	// LOG_LOCAL0|LOG_WARNING = 16<<3|4
+	if !strings.HasPrefix(msg, "<132>") {
+		t.Errorf("message %q has the wrong priority", msg)
+	}
+	if !strings.HasSuffix(msg, `level=WARN msg="disk almost full" component=db`+"\n") || strings.Contains(msg, "time=") {
+		t.Errorf("unexpected message %q", msg)
+	}
+
+	handler.SetLevel(slog.LevelDebug)
+	handler.ClearLevel()
+	if handler.Enabled(t.Context(), slog.LevelInfo) {
+		t.Error("INFO enabled after ClearLevel, want the initial level WARN")
+	}
+}
@@ -0,0 +1,124 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// OccurrencesKey is the attribute key of the number of occurrences added to
+// the records emitted by loggers from [Once] and [EveryN].
+const OccurrencesKey = "occurrences"
+
+// throttleCounters counts the occurrences of throttled records per key.
+type throttleCounters struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+// defaultThrottle holds the counters of loggers without [OverrideHandler].
+var defaultThrottle throttleCounters
+
+// add counts an occurrence of key and returns the number of occurrences.
+func (c *throttleCounters) add(key string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]uint64)
+	}
+	c.counts[key]++
+	return c.counts[key]
+}
+
+// get returns the number of occurrences of key.
+func (c *throttleCounters) get(key string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[key]
+}
+
+// Once returns a logger that emits only the first record logged through a
+// logger returned for key, e.g. for a deprecation warning logged on every
+// call:
+//
+//	slogleveloverride.Once(logger, "legacy-api").Warn("legacy API in use")
+//
+// Later records are counted and treated as suppressed by the
+// [OverrideHandler] of logger, feeding [WithSuppressedDigest],
+// [WithSuppressedBuffer] and the like. Records below the level of logger
+// are not counted.
+func Once(logger *slog.Logger, key string) *slog.Logger {
+	return EveryN(logger, key, 0)
+}
+
+// EveryN returns a logger that emits the 1st, (n+1)th, (2n+1)th... record
+// logged through a logger returned for key, with the number of occurrences
+// so far as attribute [OccurrencesKey]. Other records are counted and
+// treated like with [Once]. An n of zero or less emits the first record
+// only.
+//
+// Counters are kept per key in the [OverrideHandler] of logger, shared by
+// handlers derived from it, and can be read with
+// [OverrideHandler.Occurrences].
+func EveryN(logger *slog.Logger, key string, n int) *slog.Logger {
+	next := logger.Handler()
+	h := FindOverrideHandler(next)
+	counters := &defaultThrottle
+	if h != nil {
+		counters = &h.opts.throttle
+	}
+	return slog.New(&throttledHandler{next: next, h: h, counters: counters, key: key, n: uint64(max(n, 0))})
+}
+
+// Occurrences returns the number of records logged through loggers from
+// [Once] and [EveryN] for key.
+func (h *OverrideHandler) Occurrences(key string) uint64 {
+	return h.opts.throttle.get(key)
+}
+
+// throttledHandler emits the records of a key selected by [EveryN].
+type throttledHandler struct {
+	next     slog.Handler
+	h        *OverrideHandler
+	counters *throttleCounters
+	key      string
+	n        uint64
+}
+
+func (t *throttledHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return t.next.Enabled(ctx, level)
+}
+
+func (t *throttledHandler) Handle(ctx context.Context, record slog.Record) error {
+	if t.h != nil && !t.h.enabled(ctx, record.Level) {
+		// Suppressed anyway, possibly seen by features such as digests.
+		return t.next.Handle(ctx, record)
+	}
+	count := t.counters.add(t.key)
+	if count != 1 && (t.n == 0 || (count-1)%t.n != 0) {
+		if t.h != nil {
+			t.h.handleSuppressed(ctx, record)
+		}
+		return nil
+	}
+	record = record.Clone()
+	record.AddAttrs(slog.Uint64(OccurrencesKey, count))
+	return t.next.Handle(ctx, record)
+}
+
+// Unwrap returns the handler of the logger passed to [EveryN].
+func (t *throttledHandler) Unwrap() slog.Handler {
+	return t.next
+}
+
+func (t *throttledHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	d := *t
+	d.next = t.next.WithAttrs(attrs)
+	return &d
+}
+
+func (t *throttledHandler) WithGroup(name string) slog.Handler {
+	d := *t
+	d.next = t.next.WithGroup(name)
+	return &d
+}
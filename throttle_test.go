@@ -0,0 +1,73 @@
+package slogleveloverride
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEveryN verifies that every nth record of a key is emitted with its occurrences
+func TestEveryN(t *testing.T) {
+	var out bytes.Buffer
+	handler := NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo}, WithSuppressedDigest(time.Hour, 5))
+	logger := slog.New(handler).With("component", "cache")
+
+	for range 7 {
+		EveryN(logger, "miss", 3).Warn("cache miss")
+	}
+	for range 2 {
+		Once(logger, "legacy").Warn("legacy API in use")
+	}
+	EveryN(logger, "miss", 3).Debug("below the level")
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	want := []string{
+		`msg="cache miss" component=cache occurrences=1`,
+		`msg="cache miss" component=cache occurrences=4`,
+		`msg="cache miss" component=cache occurrences=7`,
+		`msg="legacy API in use" component=cache occurrences=1`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("output %q, want %d lines", out.String(), len(want))
+	}
+	for i := range want {
+		if !strings.Contains(lines[i], want[i]) {
+			t.Errorf("line %d is %q, want it to contain %q", i, lines[i], want[i])
+		}
+	}
+	if got := handler.Occurrences("miss"); got != 7 {
+		t.Errorf("Occurrences(miss) = %d, want 7", got)
+	}
+	record, ok := handler.opts.digest.take(time.Now().Add(2 * time.Hour))
+	if !ok {
+		t.Fatal("throttled records were not counted by the digest")
+	}
+	record.Attrs(func(a slog.Attr) bool {
+		// 4 throttled misses, 1 throttled legacy warning and the Debug record
+		if a.Key == "suppressed_total" && a.Value.Int64() != 6 {
+			t.Errorf("suppressed_total = %v, want 6", a.Value)
+		}
+		return true
+	})
+}
+
+// TestEveryNFindOverrideHandler verifies that the OverrideHandler behind a throttled logger can be found
+func TestEveryNFindOverrideHandler(t *testing.T) {
+	handler := NewWithLevel(slog.DiscardHandler, slog.LevelInfo)
+	logger := slog.New(handler)
+
+	for _, throttled := range []*slog.Logger{Once(logger, "once"), EveryN(logger.With("k", "v"), "every", 3)} {
+		if found := FindOverrideHandler(throttled.Handler()); found == nil || found.state != handler.state {
+			t.Errorf("FindOverrideHandler returned %v, want the logger's handler", found)
+		}
+		if !SetLevel(throttled.Handler(), slog.LevelDebug) {
+			t.Error("SetLevel did not find the override")
+		}
+		if !throttled.Enabled(t.Context(), slog.LevelDebug) {
+			t.Error("Debug not enabled after SetLevel through the throttled logger")
+		}
+		handler.ClearLevel()
+	}
+}
@@ -0,0 +1,89 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// throughputBudget raises the level for a while once more records than
+// allowed are forwarded within one second.
+type throughputBudget struct {
+	perSecond int64
+	level     slog.Level
+	cooldown  time.Duration
+
+	mu          sync.Mutex
+	window      rateWindow
+	raisedUntil atomic.Int64
+}
+
+// WithThroughputBudget monitors the number of records forwarded per second
+// and, once it exceeds perSecond, suppresses records below level for the
+// cooldown duration, protecting log pipelines from accidental floods. The
+// record exceeding the budget is already subject to the raised level.
+//
+// When the budget is exceeded, a Warn record explaining the change is sent
+// to the underlying handler. The budget is shared by all handlers derived
+// from the same handler.
+func WithThroughputBudget(perSecond int, level slog.Level, cooldown time.Duration) Option {
+	return func(o *options) {
+		o.throughput = &throughputBudget{
+			perSecond: int64(perSecond),
+			level:     level,
+			cooldown:  cooldown,
+		}
+	}
+}
+
+// suppresses reports whether a record at level is currently suppressed
+// because the budget was exceeded.
+func (b *throughputBudget) suppresses(level slog.Level) bool {
+	until := b.raisedUntil.Load()
+	return until != 0 && level < b.level && time.Now().UnixNano() < until
+}
+
+// countRecord accounts for a record about to be forwarded and reports
+// whether it exceeded the budget, raising the level.
+func (b *throughputBudget) countRecord() bool {
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Sub(b.window.start) >= time.Second {
+		b.window.start, b.window.count = now, 0
+	}
+	b.window.count++
+	if int64(b.window.count) != b.perSecond+1 {
+		return false
+	}
+	b.raisedUntil.Store(now.Add(b.cooldown).UnixNano())
+	return true
+}
+
+// notice returns the record announcing that the budget was exceeded.
+func (b *throughputBudget) notice() slog.Record {
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, "log throughput budget exceeded, suppressing verbose records", 0)
+	r.AddAttrs(
+		slog.Int64("budget_per_second", b.perSecond),
+		slog.String("min_level", b.level.String()),
+		slog.Duration("cooldown", b.cooldown),
+	)
+	return r
+}
+
+// countThroughput accounts for a record at level about to be forwarded,
+// sends a notice if it exceeded the budget, and reports whether the record
+// is suppressed by the raised level.
+func (h *OverrideHandler) countThroughput(ctx context.Context, level slog.Level) bool {
+	b := h.opts.throughput
+	if b == nil {
+		return false
+	}
+	if b.countRecord() {
+		_ = h.wrapped().Handle(ctx, b.notice())
+	}
+	return b.suppresses(level)
+}
@@ -0,0 +1,82 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thejerf/slogassert"
+)
+
+// TestWithThroughputBudget verifies that verbose records are suppressed once the budget is exceeded
+func TestWithThroughputBudget(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelDebug, WithThroughputBudget(2, slog.LevelWarn, time.Hour))
+	logger := slog.New(handler)
+
+	logger.Debug("debug 1")
+	logger.Debug("debug 2")
+	logger.Debug("debug exceeding")
+	logger.Debug("debug dropped")
+	logger.Error("error message")
+
+	assertHandler.AssertMessage("debug 1")
+	assertHandler.AssertMessage("debug 2")
+	assertHandler.AssertPrecise(slogassert.LogMessageMatch{
+		Message: "log throughput budget exceeded, suppressing verbose records",
+		Level:   slog.LevelWarn,
+		Attrs:   map[string]any{"budget_per_second": int64(2), "min_level": "WARN"},
+	})
+	assertHandler.AssertMessage("error message")
+}
+
+// TestThroughputBudgetCooldown verifies that the level is lowered again after the cooldown
+func TestThroughputBudgetCooldown(t *testing.T) {
+	budget := &throughputBudget{perSecond: 1, level: slog.LevelWarn, cooldown: 10 * time.Millisecond}
+
+	budget.countRecord()
+	if !budget.countRecord() {
+		t.Fatal("second record should exceed the budget")
+	}
+	if !budget.suppresses(slog.LevelInfo) {
+		t.Fatal("Info should be suppressed")
+	}
+	if budget.suppresses(slog.LevelWarn) {
+		t.Fatal("Warn should not be suppressed")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if budget.suppresses(slog.LevelInfo) {
+		t.Fatal("Info should not be suppressed after the cooldown")
+	}
+}
+
+// TestThroughputBudgetConcurrent verifies that exactly one of many concurrent records exceeds the budget
+func TestThroughputBudgetConcurrent(t *testing.T) {
+	const perSecond, goroutines, records = 100, 8, 50
+	budget := &throughputBudget{perSecond: perSecond, level: slog.LevelWarn, cooldown: time.Hour}
+
+	var exceeded atomic.Int64
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			for range records {
+				if budget.countRecord() {
+					exceeded.Add(1)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := exceeded.Load(); got != 1 {
+		t.Errorf("budget exceeded %d times, want 1", got)
+	}
+	if got := budget.window.count; got != goroutines*records {
+		t.Errorf("counted %d records, want %d", got, goroutines*records)
+	}
+}
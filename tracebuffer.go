@@ -0,0 +1,253 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// TraceIDFunc returns the ID of the trace ctx belongs to, e.g. from the span
+// context of a tracing library, or false if ctx carries none.
+type TraceIDFunc func(ctx context.Context) (string, bool)
+
+// traceBuffer holds suppressed records per key, a trace ID or the value of
+// an attribute, until a record with that key is an error.
+type traceBuffer struct {
+	traceID    TraceIDFunc
+	attrKey    string
+	maxRecords int
+	maxTraces  int
+	ttl        time.Duration
+	budget     *MemoryBudget
+
+	// now returns the current time.
+	now func() time.Time
+
+	mu     sync.Mutex
+	traces map[string]*keyBuffer
+	order  []string // keys from least to most recently buffered
+}
+
+// keyBuffer is the buffer of a key.
+type keyBuffer struct {
+	records []bufferedRecord
+	size    int64
+	last    time.Time
+}
+
+// bufferedRecord is a suppressed record along with the handler that
+// received it and its estimated size.
+type bufferedRecord struct {
+	h      *OverrideHandler
+	record slog.Record
+	size   int64
+}
+
+// WithTraceBuffer buffers the records suppressed by the level threshold per
+// trace, with the trace ID of the logging context given by traceID, and
+// forwards a trace's buffered records when that trace logs an Error record,
+// right before the error. This gives "debug on failure" per request rather
+// than globally.
+//
+// Up to maxRecords records are kept per trace, dropping the oldest, and up
+// to maxTraces traces, dropping the least recently active trace. Call
+// [OverrideHandler.EndTrace] when a trace completes to discard its records.
+//
+// To see suppressed records at all, Enabled reports true for every level
+// for contexts carrying a trace ID, so such records are built by the logger
+// even when discarded later.
+func WithTraceBuffer(traceID TraceIDFunc, maxRecords, maxTraces int) Option {
+	return func(o *options) {
+		o.traceBuffer = newTraceBuffer(maxRecords, maxTraces, 0)
+		o.traceBuffer.traceID = traceID
+	}
+}
+
+// WithKeyedBuffer is like [WithTraceBuffer], with records grouped by the
+// value of the attribute key, such as "request_id" or "job_id", of the
+// record or of the logger, rather than by trace. Records without the
+// attribute are not buffered.
+//
+// Buffers not added to for ttl are discarded; a ttl of zero keeps them until
+// they are flushed, discarded with [OverrideHandler.EndKey] or dropped
+// beyond maxKeys. Since attributes are only known in Handle, Enabled reports
+// true for every level.
+func WithKeyedBuffer(key string, maxRecords, maxKeys int, ttl time.Duration) Option {
+	return func(o *options) {
+		o.traceBuffer = newTraceBuffer(maxRecords, maxKeys, ttl)
+		o.traceBuffer.attrKey = key
+	}
+}
+
+// newTraceBuffer returns an empty traceBuffer with the given limits.
+func newTraceBuffer(maxRecords, maxKeys int, ttl time.Duration) *traceBuffer {
+	return &traceBuffer{
+		maxRecords: max(maxRecords, 1),
+		maxTraces:  max(maxKeys, 1),
+		ttl:        ttl,
+		traces:     make(map[string]*keyBuffer),
+	}
+}
+
+// traced reports whether records logged with ctx may have a key.
+func (b *traceBuffer) traced(ctx context.Context) bool {
+	if b.traceID == nil {
+		return true
+	}
+	if ctx == nil {
+		return false
+	}
+	_, ok := b.traceID(ctx)
+	return ok
+}
+
+// key returns the key of record, received by h with ctx.
+func (b *traceBuffer) key(ctx context.Context, h *OverrideHandler, record slog.Record) (string, bool) {
+	if b.traceID == nil {
+		value, ok := h.lookupAttr(record, b.attrKey)
+		if !ok {
+			return "", false
+		}
+		return value.String(), true
+	}
+	if ctx == nil {
+		return "", false
+	}
+	return b.traceID(ctx)
+}
+
+// clock returns the current time.
+func (b *traceBuffer) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
+
+// add buffers record, received by h, for its key, if any.
+func (b *traceBuffer) add(ctx context.Context, h *OverrideHandler, record slog.Record) {
+	id, ok := b.key(ctx, h, record)
+	if !ok {
+		return
+	}
+	now := b.clock()
+	size := recordSize(record)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expire(now)
+	for !b.budget.reserve(size) {
+		if len(b.order) == 0 {
+			b.budget.reject()
+			return
+		}
+		b.evictOldest()
+	}
+	buf, ok := b.traces[id]
+	if ok {
+		i := slices.Index(b.order, id)
+		b.order = slices.Delete(b.order, i, i+1)
+	} else {
+		if len(b.order) >= b.maxTraces {
+			b.drop(b.order[0])
+		}
+		buf = &keyBuffer{}
+		b.traces[id] = buf
+	}
+	b.order = append(b.order, id)
+	if len(buf.records) >= b.maxRecords {
+		buf.size -= buf.records[0].size
+		b.budget.release(buf.records[0].size)
+		buf.records = buf.records[1:]
+	}
+	buf.records = append(buf.records, bufferedRecord{h: h, record: record.Clone(), size: size})
+	buf.size += size
+	buf.last = now
+}
+
+// evictOldest evicts the oldest record of the least recently buffered key
+// to make room in the budget. The caller must hold b.mu.
+func (b *traceBuffer) evictOldest() {
+	id := b.order[0]
+	buf := b.traces[id]
+	b.budget.evict(buf.records[0].size)
+	buf.size -= buf.records[0].size
+	buf.records = buf.records[1:]
+	if len(buf.records) == 0 {
+		delete(b.traces, id)
+		b.order = b.order[1:]
+	}
+}
+
+// drop discards the buffer of id, which must be the least recently buffered
+// key. The caller must hold b.mu.
+func (b *traceBuffer) drop(id string) {
+	b.budget.release(b.traces[id].size)
+	delete(b.traces, id)
+	b.order = b.order[1:]
+}
+
+// expire discards the buffers not added to for the ttl of b. The caller
+// must hold b.mu.
+func (b *traceBuffer) expire(now time.Time) {
+	if b.ttl <= 0 {
+		return
+	}
+	for len(b.order) > 0 && now.Sub(b.traces[b.order[0]].last) >= b.ttl {
+		b.drop(b.order[0])
+	}
+}
+
+// take removes and returns the records buffered for id.
+func (b *traceBuffer) take(id string) []bufferedRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expire(b.clock())
+	buf, ok := b.traces[id]
+	if !ok {
+		return nil
+	}
+	b.budget.release(buf.size)
+	delete(b.traces, id)
+	if i := slices.Index(b.order, id); i >= 0 {
+		b.order = slices.Delete(b.order, i, i+1)
+	}
+	return buf.records
+}
+
+// EndTrace discards the records buffered with [WithTraceBuffer] for the
+// trace of ctx.
+func (h *OverrideHandler) EndTrace(ctx context.Context) {
+	b := h.opts.traceBuffer
+	if b == nil || b.traceID == nil || ctx == nil {
+		return
+	}
+	if id, ok := b.traceID(ctx); ok {
+		b.take(id)
+	}
+}
+
+// EndKey discards the records buffered with [WithKeyedBuffer] for the
+// attribute value, formatted as by [slog.Value.String].
+func (h *OverrideHandler) EndKey(value string) {
+	if b := h.opts.traceBuffer; b != nil && b.traceID == nil {
+		b.take(value)
+	}
+}
+
+// flushTrace forwards the records buffered for the key of record if record
+// is an error.
+func (h *OverrideHandler) flushTrace(ctx context.Context, record slog.Record) {
+	b := h.opts.traceBuffer
+	if b == nil || record.Level < slog.LevelError {
+		return
+	}
+	id, ok := b.key(ctx, h, record)
+	if !ok {
+		return
+	}
+	for _, r := range b.take(id) {
+		_ = r.h.forward(ctx, r.record)
+	}
+}
@@ -0,0 +1,111 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/thejerf/slogassert"
+)
+
+type traceKey struct{}
+
+func withTrace(id string) context.Context {
+	return context.WithValue(context.Background(), traceKey{}, id)
+}
+
+func testTraceID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(traceKey{}).(string)
+	return id, ok
+}
+
+// TestTraceBuffer verifies that a trace's suppressed records are forwarded only if it logs an error
+func TestTraceBuffer(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelInfo, WithTraceBuffer(testTraceID, 2, 8))
+	logger := slog.New(handler)
+	failing, succeeding := withTrace("failing"), withTrace("succeeding")
+
+	logger.DebugContext(failing, "dropped by record limit")
+	logger.DebugContext(failing, "failing debug 1")
+	logger.DebugContext(succeeding, "succeeding debug")
+	logger.DebugContext(context.Background(), "untraced debug")
+	logger.DebugContext(failing, "failing debug 2")
+	logger.InfoContext(succeeding, "succeeding info")
+
+	logger.ErrorContext(failing, "failing error")
+	handler.EndTrace(succeeding)
+	logger.ErrorContext(succeeding, "succeeding error after end")
+
+	assertHandler.AssertMessage("succeeding info")
+	assertHandler.AssertMessage("failing debug 1")
+	assertHandler.AssertMessage("failing debug 2")
+	assertHandler.AssertMessage("failing error")
+	assertHandler.AssertMessage("succeeding error after end")
+}
+
+// TestTraceBufferMaxTraces verifies that the oldest trace is dropped beyond the limit
+func TestTraceBufferMaxTraces(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelInfo, WithTraceBuffer(testTraceID, 8, 1))
+	logger := slog.New(handler)
+
+	logger.DebugContext(withTrace("old"), "old debug")
+	logger.DebugContext(withTrace("new"), "new debug")
+	logger.ErrorContext(withTrace("old"), "old error")
+	logger.ErrorContext(withTrace("new"), "new error")
+
+	assertHandler.AssertMessage("old error")
+	assertHandler.AssertMessage("new debug")
+	assertHandler.AssertMessage("new error")
+}
+
+// TestKeyedBuffer verifies that records are buffered per attribute value and flushed on error
+func TestKeyedBuffer(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelInfo, WithKeyedBuffer("job_id", 8, 8, 0))
+	logger := slog.New(handler)
+	failing := logger.With("job_id", 1)
+
+	failing.Debug("failing debug")
+	logger.Debug("other debug", "job_id", 2)
+	logger.Debug("unkeyed debug")
+	logger.Error("failing error", "job_id", 1)
+	handler.EndKey("2")
+	logger.Error("other error", "job_id", 2)
+
+	assertHandler.AssertMessage("failing debug")
+	assertHandler.AssertMessage("failing error")
+	assertHandler.AssertMessage("other error")
+}
+
+// TestKeyedBufferTTL verifies that idle buffers are evicted after the TTL
+func TestKeyedBufferTTL(t *testing.T) {
+	assertHandler := slogassert.New(t, slog.LevelDebug, nil)
+	defer assertHandler.AssertEmpty()
+
+	handler := NewWithLevel(assertHandler, slog.LevelInfo, WithKeyedBuffer("request_id", 8, 8, time.Minute))
+	now := time.Now()
+	handler.opts.traceBuffer.now = func() time.Time { return now }
+	logger := slog.New(handler)
+
+	logger.Debug("idle debug", "request_id", "idle")
+	logger.Debug("active debug 1", "request_id", "active")
+	now = now.Add(45 * time.Second)
+	logger.Debug("active debug 2", "request_id", "active")
+	now = now.Add(30 * time.Second)
+	logger.Error("idle error", "request_id", "idle")
+	logger.Error("active error", "request_id", "active")
+
+	assertHandler.AssertMessage("idle error")
+	assertHandler.AssertMessage("active debug 1")
+	assertHandler.AssertMessage("active debug 2")
+	assertHandler.AssertMessage("active error")
+}
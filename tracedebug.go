@@ -0,0 +1,78 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// jaegerDebugFlag is the flag bit of the uber-trace-id header marking a
+// trace for debugging.
+const jaegerDebugFlag = 0x02
+
+// TraceDebug enables Debug for requests whose incoming trace context is
+// marked for debugging, by setting the level on the request context with
+// [ContextWithLevel] for handlers created with [WithContextLevels].
+//
+// A request is marked for debugging if it carries a jaeger-debug-id header,
+// if the flags of its uber-trace-id header have the Jaeger debug bit set,
+// or if the trace-flags of its traceparent header have any of the bits of
+// TraceFlags set.
+//
+// The zero value is enabled and only honors the Jaeger headers. It can be
+// switched off and on at runtime with SetEnabled.
+type TraceDebug struct {
+	// TraceFlags is the mask of traceparent trace-flags bits marking a
+	// request for debugging. Zero ignores traceparent.
+	TraceFlags byte
+
+	disabled atomic.Bool
+}
+
+// SetEnabled switches the detection of debug requests on or off.
+func (d *TraceDebug) SetEnabled(enabled bool) {
+	d.disabled.Store(!enabled)
+}
+
+// Enabled reports whether debug requests are detected.
+func (d *TraceDebug) Enabled() bool {
+	return !d.disabled.Load()
+}
+
+// Middleware wraps next so that requests marked for debugging are handled
+// with Debug enabled.
+func (d *TraceDebug) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d.Enabled() && d.debug(r.Header) {
+			r = r.WithContext(ContextWithLevel(r.Context(), slog.LevelDebug))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// debug reports whether the trace context in h marks the request for
+// debugging.
+func (d *TraceDebug) debug(h http.Header) bool {
+	if h.Get("Jaeger-Debug-Id") != "" {
+		return true
+	}
+	if id := h.Get("Uber-Trace-Id"); id != "" {
+		parts := strings.Split(id, ":")
+		if len(parts) == 4 {
+			if flags, err := strconv.ParseUint(parts[3], 16, 8); err == nil && flags&jaegerDebugFlag != 0 {
+				return true
+			}
+		}
+	}
+	if d.TraceFlags != 0 {
+		parts := strings.Split(h.Get("Traceparent"), "-")
+		if len(parts) >= 4 {
+			if flags, err := strconv.ParseUint(parts[3], 16, 8); err == nil && byte(flags)&d.TraceFlags != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package slogleveloverride
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestTraceDebug verifies that requests marked for debugging by their trace context get Debug enabled
+func TestTraceDebug(t *testing.T) {
+	logger := slog.New(NewWithLevel(slog.DiscardHandler, slog.LevelInfo, WithContextLevels()))
+	var debug TraceDebug
+	var enabled bool
+	handler := debug.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enabled = logger.Enabled(r.Context(), slog.LevelDebug)
+	}))
+
+	tests := []struct {
+		name       string
+		traceFlags byte
+		header     string
+		value      string
+		want       bool
+	}{
+		{"jaeger debug id", 0, "Jaeger-Debug-Id", "ticket-42", true},
+		{"jaeger debug flag", 0, "Uber-Trace-Id", "4bf92f3577b34da6:00f067aa0ba902b7:0:3", true},
+		{"jaeger sampled flag", 0, "Uber-Trace-Id", "4bf92f3577b34da6:00f067aa0ba902b7:0:1", false},
+		{"traceparent ignored", 0, "Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"traceparent flag", 0x01, "Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"no trace context", 0x01, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			debug.TraceFlags = tt.traceFlags
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if enabled != tt.want {
+				t.Errorf("debug enabled = %v, want %v", enabled, tt.want)
+			}
+		})
+	}
+
+	debug.SetEnabled(false)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Jaeger-Debug-Id", "ticket-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if enabled {
+		t.Error("debug should not be enabled once switched off")
+	}
+}
@@ -0,0 +1,149 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+)
+
+// namedLevels are the steps used by IncreaseVerbosity and DecreaseVerbosity.
+var namedLevels = []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+
+// probeRange bounds the levels considered by handlerLevel.
+const probeRange = 256
+
+// handlerLevel returns the lowest level h is enabled for, assuming that a
+// handler enabled for some level is also enabled for all higher ones.
+//
+// Levels are searched in [-probeRange, probeRange]. If h is enabled for no
+// level in that range, probeRange+1 is returned.
+func handlerLevel(ctx context.Context, h slog.Handler) slog.Level {
+	lo, hi := -probeRange, probeRange+1
+	for lo < hi {
+		mid := (lo + hi) >> 1
+		if h.Enabled(ctx, slog.Level(mid)) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return slog.Level(lo)
+}
+
+// currentLevel returns the level of the override, or the level of the
+// underlying handler if no override is set.
+func (h *OverrideHandler) currentLevel(ctx context.Context) slog.Level {
+	if level, ok := h.Level(); ok {
+		return level
+	}
+	return handlerLevel(ctx, h.wrapped())
+}
+
+// effectiveLevel returns the threshold records are compared with: the
+// global override if set, or else the result of currentLevel.
+func (h *OverrideHandler) effectiveLevel(ctx context.Context) slog.Level {
+	if leveler, ok := globalState.load(); ok {
+		return leveler.Level()
+	}
+	return h.currentLevel(ctx)
+}
+
+// IncreaseVerbosity moves the override one named level down, e.g. from
+// [slog.LevelInfo] to [slog.LevelDebug], and returns the new level.
+//
+// The step starts from the current override, or from the underlying
+// handler's level if no override is set. Levels between named ones move to
+// the next named level; [slog.LevelDebug] is the most verbose step. If the
+// policy set with [SetPolicy] rejects the change, the current level is
+// returned.
+func (h *OverrideHandler) IncreaseVerbosity() slog.Level {
+	return h.increaseVerbosity("")
+}
+
+// increaseVerbosity implements IncreaseVerbosity, checking the change
+// against the policy with target.
+func (h *OverrideHandler) increaseVerbosity(target string) slog.Level {
+	current := h.currentLevel(context.Background())
+	next := namedLevels[0]
+	for _, level := range namedLevels {
+		if level < current {
+			next = level
+		}
+	}
+	if h.setLevel(target, SourceCode, next) != nil {
+		return current
+	}
+	return next
+}
+
+// DecreaseVerbosity moves the override one named level up, e.g. from
+// [slog.LevelInfo] to [slog.LevelWarn], and returns the new level.
+//
+// The step starts from the current override, or from the underlying
+// handler's level if no override is set. Levels between named ones move to
+// the next named level; [slog.LevelError] is the least verbose step. If the
+// policy set with [SetPolicy] rejects the change, the current level is
+// returned.
+func (h *OverrideHandler) DecreaseVerbosity() slog.Level {
+	return h.decreaseVerbosity("")
+}
+
+// decreaseVerbosity implements DecreaseVerbosity, checking the change
+// against the policy with target.
+func (h *OverrideHandler) decreaseVerbosity(target string) slog.Level {
+	current := h.currentLevel(context.Background())
+	next := namedLevels[len(namedLevels)-1]
+	for i := len(namedLevels) - 1; i >= 0; i-- {
+		if namedLevels[i] > current {
+			next = namedLevels[i]
+		}
+	}
+	if h.setLevel(target, SourceCode, next) != nil {
+		return current
+	}
+	return next
+}
+
+// IncreaseVerbosity calls [OverrideHandler.IncreaseVerbosity] on the handler
+// registered under name. Returns false if no handler is registered under
+// name.
+func (r *Registry) IncreaseVerbosity(name string) (slog.Level, bool) {
+	h, ok := r.Handler(name)
+	if !ok {
+		return 0, false
+	}
+	return h.increaseVerbosity(name), true
+}
+
+// DecreaseVerbosity calls [OverrideHandler.DecreaseVerbosity] on the handler
+// registered under name. Returns false if no handler is registered under
+// name.
+func (r *Registry) DecreaseVerbosity(name string) (slog.Level, bool) {
+	h, ok := r.Handler(name)
+	if !ok {
+		return 0, false
+	}
+	return h.decreaseVerbosity(name), true
+}
+
+// OffsetLeveler is a [slog.Leveler] whose level is the level of Handler
+// shifted by Delta, so that an override can be "4 levels more verbose than
+// the base handler" and follow changes of the base handler's level.
+//
+// The level of Handler is probed through its Enabled method on every call
+// to Level. Handler must not be the [OverrideHandler] the leveler is set on,
+// but the handler it wraps.
+type OffsetLeveler struct {
+	Handler slog.Handler
+	Delta   int
+}
+
+// NewOffsetLeveler creates an [OffsetLeveler] for h shifted by delta.
+// A negative delta is more verbose than h.
+func NewOffsetLeveler(h slog.Handler, delta int) *OffsetLeveler {
+	return &OffsetLeveler{Handler: h, Delta: delta}
+}
+
+// Level returns the level of Handler shifted by Delta.
+func (l *OffsetLeveler) Level() slog.Level {
+	return handlerLevel(context.Background(), l.Handler) + slog.Level(l.Delta)
+}
@@ -0,0 +1,83 @@
+package slogleveloverride
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// TestHandlerLevel verifies probing the level of a handler
+func TestHandlerLevel(t *testing.T) {
+	for _, want := range []slog.Level{slog.LevelDebug, slog.LevelInfo + 1, slog.LevelError, -probeRange} {
+		h := slog.NewTextHandler(nil, &slog.HandlerOptions{Level: want})
+		if got := handlerLevel(context.Background(), h); got != want {
+			t.Errorf("handlerLevel = %v, want %v", got, want)
+		}
+	}
+	if got := handlerLevel(context.Background(), slog.DiscardHandler); got != probeRange+1 {
+		t.Errorf("handlerLevel(DiscardHandler) = %v, want %v", got, probeRange+1)
+	}
+}
+
+// TestVerbositySteps verifies stepping through the named levels
+func TestVerbositySteps(t *testing.T) {
+	base := slog.NewTextHandler(nil, &slog.HandlerOptions{Level: slog.LevelWarn})
+	handler := New(base)
+
+	// Without override, steps start from the underlying handler's level
+	if got := handler.IncreaseVerbosity(); got != slog.LevelInfo {
+		t.Fatalf("IncreaseVerbosity = %v, want %v", got, slog.LevelInfo)
+	}
+	if got := handler.IncreaseVerbosity(); got != slog.LevelDebug {
+		t.Fatalf("IncreaseVerbosity = %v, want %v", got, slog.LevelDebug)
+	}
+	if got := handler.IncreaseVerbosity(); got != slog.LevelDebug {
+		t.Fatalf("IncreaseVerbosity = %v, want %v", got, slog.LevelDebug)
+	}
+
+	// Levels between named ones move to the next named level
+	handler.SetLevel(slog.LevelInfo + 2)
+	if got := handler.DecreaseVerbosity(); got != slog.LevelWarn {
+		t.Fatalf("DecreaseVerbosity = %v, want %v", got, slog.LevelWarn)
+	}
+	if got := handler.DecreaseVerbosity(); got != slog.LevelError {
+		t.Fatalf("DecreaseVerbosity = %v, want %v", got, slog.LevelError)
+	}
+	if got := handler.DecreaseVerbosity(); got != slog.LevelError {
+		t.Fatalf("DecreaseVerbosity = %v, want %v", got, slog.LevelError)
+	}
+
+	registry := NewRegistry()
+	registry.Register("db", handler)
+	if got, ok := registry.IncreaseVerbosity("db"); !ok || got != slog.LevelWarn {
+		t.Fatalf("Registry.IncreaseVerbosity = (%v, %v), want (%v, true)", got, ok, slog.LevelWarn)
+	}
+	if _, ok := registry.DecreaseVerbosity("http"); ok {
+		t.Fatal("Registry.DecreaseVerbosity should return false for an unknown name")
+	}
+}
+
+// TestOffsetLeveler verifies that the offset follows the base handler's level
+func TestOffsetLeveler(t *testing.T) {
+	var baseLevel slog.LevelVar
+	baseLevel.Set(slog.LevelWarn)
+	base := slog.NewTextHandler(nil, &slog.HandlerOptions{Level: &baseLevel})
+
+	leveler := NewOffsetLeveler(base, -4)
+	if got := leveler.Level(); got != slog.LevelInfo {
+		t.Fatalf("Level = %v, want %v", got, slog.LevelInfo)
+	}
+
+	baseLevel.Set(slog.LevelError)
+	if got := leveler.Level(); got != slog.LevelWarn {
+		t.Fatalf("Level = %v, want %v", got, slog.LevelWarn)
+	}
+
+	handler := NewWithLevel(base, leveler)
+	if !handler.Enabled(context.Background(), slog.LevelWarn) {
+		t.Fatal("handler should be enabled for Warn")
+	}
+	if handler.Enabled(context.Background(), slog.LevelInfo) {
+		t.Fatal("handler should not be enabled for Info")
+	}
+}